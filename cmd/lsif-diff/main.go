@@ -0,0 +1,112 @@
+// Command lsif-diff reports the differences in definitions, references, hover text, and
+// monikers between two LSIF dumps of the same project. It exits with status 1 if the dumps
+// differ.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/sourcegraph/lsif-protocol/diff"
+	"github.com/sourcegraph/lsif-protocol/reader"
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s <old.lsif> <new.lsif>\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() != 2 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	diffs, err := run(flag.Arg(0), flag.Arg(1))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "lsif-diff: %s\n", err)
+		os.Exit(2)
+	}
+
+	if err := format(os.Stdout, diffs); err != nil {
+		fmt.Fprintf(os.Stderr, "lsif-diff: %s\n", err)
+		os.Exit(2)
+	}
+
+	if len(diffs) > 0 {
+		os.Exit(1)
+	}
+}
+
+func run(oldName, newName string) ([]diff.DocumentDiff, error) {
+	oldGraph, err := correlate(oldName)
+	if err != nil {
+		return nil, err
+	}
+
+	newGraph, err := correlate(newName)
+	if err != nil {
+		return nil, err
+	}
+
+	return diff.Diff(oldGraph, newGraph), nil
+}
+
+func correlate(name string) (*reader.Graph, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	g, err := reader.Correlate(context.Background(), f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", name, err)
+	}
+
+	return g, nil
+}
+
+// format writes a human-readable representation of the given differences.
+func format(w io.Writer, diffs []diff.DocumentDiff) error {
+	var b strings.Builder
+
+	for _, d := range diffs {
+		fmt.Fprintf(&b, "%s (%s)\n", d.Path, d.Status)
+
+		if d.Status != "modified" {
+			fmt.Fprintf(&b, "  %d ranges\n", len(d.AddedRanges)+len(d.RemovedRanges))
+			continue
+		}
+
+		for _, r := range d.RemovedRanges {
+			fmt.Fprintf(&b, "  - range %s\n", r)
+		}
+		for _, r := range d.AddedRanges {
+			fmt.Fprintf(&b, "  + range %s\n", r)
+		}
+
+		for _, change := range d.Changes {
+			fmt.Fprintf(&b, "  ~ %s %s\n", change.Range, change.Field)
+			for _, value := range change.Removed {
+				fmt.Fprintf(&b, "      - %s\n", indent(value))
+			}
+			for _, value := range change.Added {
+				fmt.Fprintf(&b, "      + %s\n", indent(value))
+			}
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// indent aligns the continuation lines of multi-line values (such as hover text).
+func indent(value string) string {
+	return strings.Replace(value, "\n", "\n        ", -1)
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRun(t *testing.T) {
+	oldName := writeDump(t, "old.lsif", []string{
+		`{"id": 1, "type": "vertex", "label": "metaData", "version": "0.4.3", "projectRoot": "file:///build/a"}`,
+		`{"id": 2, "type": "vertex", "label": "document", "uri": "file:///build/a/foo.go"}`,
+		`{"id": 3, "type": "vertex", "label": "range", "start": {"line": 1, "character": 5}, "end": {"line": 1, "character": 8}}`,
+		`{"id": 4, "type": "edge", "label": "contains", "outV": 2, "inVs": [3]}`,
+		`{"id": 5, "type": "vertex", "label": "hoverResult", "result": {"contents": "func Foo()"}}`,
+		`{"id": 6, "type": "edge", "label": "textDocument/hover", "outV": 3, "inV": 5}`,
+		`{"id": 7, "type": "vertex", "label": "document", "uri": "file:///build/a/old.go"}`,
+	})
+	newName := writeDump(t, "new.lsif", []string{
+		`{"id": 1, "type": "vertex", "label": "metaData", "version": "0.4.3", "projectRoot": "file:///build/b"}`,
+		`{"id": 2, "type": "vertex", "label": "document", "uri": "file:///build/b/foo.go"}`,
+		`{"id": 3, "type": "vertex", "label": "range", "start": {"line": 1, "character": 5}, "end": {"line": 1, "character": 8}}`,
+		`{"id": 4, "type": "edge", "label": "contains", "outV": 2, "inVs": [3]}`,
+		`{"id": 5, "type": "vertex", "label": "hoverResult", "result": {"contents": "func Foo(x int)"}}`,
+		`{"id": 6, "type": "edge", "label": "textDocument/hover", "outV": 3, "inV": 5}`,
+	})
+
+	diffs, err := run(oldName, newName)
+	if err != nil {
+		t.Fatalf("unexpected error diffing dumps: %s", err)
+	}
+
+	var buf bytes.Buffer
+	if err := format(&buf, diffs); err != nil {
+		t.Fatalf("unexpected error formatting differences: %s", err)
+	}
+
+	expected := strings.Join([]string{
+		"foo.go (modified)",
+		"  ~ 1:5-1:8 hover",
+		"      - func Foo()",
+		"      + func Foo(x int)",
+		"old.go (removed)",
+		"  0 ranges",
+	}, "\n") + "\n"
+	if diff := cmp.Diff(expected, buf.String()); diff != "" {
+		t.Errorf("unexpected report (-want +got):\n%s", diff)
+	}
+}
+
+func TestRunIdentical(t *testing.T) {
+	name := writeDump(t, "dump.lsif", []string{
+		`{"id": 1, "type": "vertex", "label": "metaData", "version": "0.4.3", "projectRoot": "file:///build"}`,
+		`{"id": 2, "type": "vertex", "label": "document", "uri": "file:///build/foo.go"}`,
+	})
+
+	diffs, err := run(name, name)
+	if err != nil {
+		t.Fatalf("unexpected error diffing dumps: %s", err)
+	}
+	if len(diffs) != 0 {
+		t.Errorf("unexpected differences: %v", diffs)
+	}
+}
+
+func TestRunMalformedDump(t *testing.T) {
+	name := writeDump(t, "dump.lsif", []string{`{"id": 1, "type": "vertex"`})
+
+	if _, err := run(name, name); err == nil || !strings.HasPrefix(err.Error(), name+": ") {
+		t.Errorf("expected error prefixed by the dump name, have %v", err)
+	}
+}
+
+// writeDump writes the given lines to a dump file with the given name and returns its path.
+func writeDump(t *testing.T, name string, lines []string) string {
+	name = filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(name, []byte(strings.Join(lines, "\n")), 0644); err != nil {
+		t.Fatalf("unexpected error writing dump: %s", err)
+	}
+
+	return name
+}
@@ -0,0 +1,49 @@
+// Command lsif-lsp serves code navigation from an LSIF dump to an editor over stdio using the
+// Language Server Protocol.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/sourcegraph/lsif-protocol/query"
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s <dump.lsif>\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	// stdout carries the protocol, so diagnostics must go elsewhere
+	log.SetOutput(os.Stderr)
+	log.SetPrefix("lsif-lsp: ")
+
+	if err := run(flag.Arg(0)); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	index, err := query.Load(context.Background(), f)
+	if err != nil {
+		return fmt.Errorf("loading %s: %s", path, err)
+	}
+
+	return newServer(index).serve(os.Stdin, os.Stdout)
+}
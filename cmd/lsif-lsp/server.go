@@ -0,0 +1,338 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"strings"
+
+	protocol "github.com/sourcegraph/lsif-protocol"
+	"github.com/sourcegraph/lsif-protocol/query"
+	"github.com/sourcegraph/lsif-protocol/reader"
+)
+
+type server struct {
+	index *query.Index
+
+	// clientRoot and dumpRoot are the root URIs of the workspace opened in the editor and
+	// of the project that was indexed, respectively. URIs under one root are translated
+	// into URIs under the other. Both roots end with a slash when non-empty.
+	clientRoot string
+	dumpRoot   string
+}
+
+func newServer(index *query.Index) *server {
+	return &server{
+		index:    index,
+		dumpRoot: reader.WithTrailingSlash(index.Graph().MetaData.ProjectRoot),
+	}
+}
+
+// serve reads requests from the given reader and writes responses to the given writer
+// until the input is exhausted or the client sends an exit notification.
+func (s *server) serve(in io.Reader, out io.Writer) error {
+	r := bufio.NewReader(in)
+
+	for {
+		body, err := readMessage(r)
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+
+			return err
+		}
+
+		var req request
+		if err := json.Unmarshal(body, &req); err != nil {
+			if err := writeMessage(out, errorResponse{JSONRPC: "2.0", Error: &rpcError{Code: codeParseError, Message: err.Error()}}); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if req.Method == "exit" {
+			return nil
+		}
+
+		result, err := s.handle(req.Method, req.Params)
+		if req.ID == nil {
+			// Notifications do not receive a response
+			continue
+		}
+
+		if err != nil {
+			rpcErr, ok := err.(*rpcError)
+			if !ok {
+				rpcErr = &rpcError{Code: codeInternalError, Message: err.Error()}
+			}
+
+			if err := writeMessage(out, errorResponse{JSONRPC: "2.0", ID: req.ID, Error: rpcErr}); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if err := writeMessage(out, response{JSONRPC: "2.0", ID: req.ID, Result: result}); err != nil {
+			return err
+		}
+	}
+}
+
+type initializeParams struct {
+	RootURI string `json:"rootUri"`
+}
+
+type textDocumentIdentifier struct {
+	URI string `json:"uri"`
+}
+
+type textDocumentPositionParams struct {
+	TextDocument textDocumentIdentifier `json:"textDocument"`
+	Position     protocol.Pos           `json:"position"`
+}
+
+type documentSymbolParams struct {
+	TextDocument textDocumentIdentifier `json:"textDocument"`
+}
+
+type location struct {
+	URI   string             `json:"uri"`
+	Range protocol.RangeData `json:"range"`
+}
+
+type markupContent struct {
+	Kind  string `json:"kind"`
+	Value string `json:"value"`
+}
+
+type hover struct {
+	Contents markupContent      `json:"contents"`
+	Range    protocol.RangeData `json:"range"`
+}
+
+func (s *server) handle(method string, params json.RawMessage) (interface{}, error) {
+	switch method {
+	case "initialize":
+		var p initializeParams
+		if err := unmarshalParams(params, &p); err != nil {
+			return nil, err
+		}
+		s.clientRoot = reader.WithTrailingSlash(p.RootURI)
+
+		return map[string]interface{}{
+			"capabilities": map[string]interface{}{
+				"definitionProvider":     true,
+				"referencesProvider":     true,
+				"hoverProvider":          true,
+				"implementationProvider": true,
+				"documentSymbolProvider": true,
+			},
+		}, nil
+
+	case "initialized":
+		return nil, nil
+
+	case "shutdown":
+		return nil, nil
+
+	case "textDocument/definition":
+		return s.locations(params, s.index.Definitions)
+
+	case "textDocument/references":
+		return s.locations(params, s.index.References)
+
+	case "textDocument/implementation":
+		return s.locations(params, s.index.Implementations)
+
+	case "textDocument/hover":
+		var p textDocumentPositionParams
+		if err := unmarshalParams(params, &p); err != nil {
+			return nil, err
+		}
+
+		uri := s.toDumpURI(p.TextDocument.URI)
+		for _, id := range s.index.Ranges(uri, p.Position) {
+			if text, ok := s.index.Graph().Hover(id); ok {
+				r, _ := s.index.Graph().Range(id)
+				return hover{Contents: markupContent{Kind: "markdown", Value: text}, Range: convertRange(r)}, nil
+			}
+		}
+
+		return nil, nil
+
+	case "textDocument/documentSymbol":
+		var p documentSymbolParams
+		if err := unmarshalParams(params, &p); err != nil {
+			return nil, err
+		}
+
+		return s.documentSymbols(s.toDumpURI(p.TextDocument.URI)), nil
+	}
+
+	if strings.HasPrefix(method, "$/") {
+		// Implementation-dependent notifications may be ignored
+		return nil, nil
+	}
+
+	return nil, &rpcError{Code: codeMethodNotFound, Message: "method not found: " + method}
+}
+
+func (s *server) locations(params json.RawMessage, f func(uri string, pos protocol.Pos) []reader.Location) (interface{}, error) {
+	var p textDocumentPositionParams
+	if err := unmarshalParams(params, &p); err != nil {
+		return nil, err
+	}
+
+	locations := []location{}
+	for _, l := range f(s.toDumpURI(p.TextDocument.URI), p.Position) {
+		locations = append(locations, location{URI: s.toClientURI(l.URI), Range: convertRange(l.Range)})
+	}
+
+	return locations, nil
+}
+
+// documentSymbols returns the document symbols of the given document. The payload of the
+// document's documentSymbolResult is used when present; otherwise, symbols are derived from
+// the declaration and definition tags of the document's ranges.
+func (s *server) documentSymbols(uri string) []protocol.DocumentSymbol {
+	graph := s.index.Graph()
+
+	documentID, ok := s.index.Document(uri)
+	if !ok {
+		return []protocol.DocumentSymbol{}
+	}
+
+	if payload, ok := graph.DocumentSymbols(documentID); ok {
+		switch symbols := payload.(type) {
+		case []reader.DocumentSymbol:
+			return convertDocumentSymbols(symbols)
+		case []reader.RangeBasedDocumentSymbol:
+			return s.convertRangeBasedDocumentSymbols(symbols)
+		}
+	}
+
+	symbols := []protocol.DocumentSymbol{}
+	for _, id := range graph.DocumentRanges(documentID) {
+		if symbol, ok := s.rangeSymbol(id); ok {
+			symbols = append(symbols, symbol)
+		}
+	}
+
+	return symbols
+}
+
+func convertDocumentSymbols(symbols []reader.DocumentSymbol) []protocol.DocumentSymbol {
+	converted := []protocol.DocumentSymbol{}
+	for _, symbol := range symbols {
+		var tags []protocol.SymbolTag
+		for _, tag := range symbol.Tags {
+			tags = append(tags, protocol.SymbolTag(tag))
+		}
+
+		converted = append(converted, protocol.DocumentSymbol{
+			Name:       symbol.Name,
+			Detail:     symbol.Detail,
+			Kind:       protocol.SymbolKind(symbol.Kind),
+			Tags:       tags,
+			Deprecated: symbol.Deprecated,
+			Range: protocol.RangeData{
+				Start: protocol.Pos{Line: symbol.StartLine, Character: symbol.StartCharacter},
+				End:   protocol.Pos{Line: symbol.EndLine, Character: symbol.EndCharacter},
+			},
+			SelectionRange: protocol.RangeData{
+				Start: protocol.Pos{Line: symbol.SelectionStartLine, Character: symbol.SelectionStartCharacter},
+				End:   protocol.Pos{Line: symbol.SelectionEndLine, Character: symbol.SelectionEndCharacter},
+			},
+			Children: convertDocumentSymbols(symbol.Children),
+		})
+	}
+
+	return converted
+}
+
+func (s *server) convertRangeBasedDocumentSymbols(symbols []reader.RangeBasedDocumentSymbol) []protocol.DocumentSymbol {
+	converted := []protocol.DocumentSymbol{}
+	for _, symbol := range symbols {
+		documentSymbol, ok := s.rangeSymbol(symbol.ID)
+		if !ok {
+			continue
+		}
+
+		documentSymbol.Children = s.convertRangeBasedDocumentSymbols(symbol.Children)
+		converted = append(converted, documentSymbol)
+	}
+
+	return converted
+}
+
+// rangeSymbol creates a document symbol from the declaration or definition tag of the given range.
+func (s *server) rangeSymbol(id int) (protocol.DocumentSymbol, bool) {
+	r, ok := s.index.Graph().Range(id)
+	if !ok || r.Tag == nil || (r.Tag.Type != "declaration" && r.Tag.Type != "definition") {
+		return protocol.DocumentSymbol{}, false
+	}
+
+	fullRange := r
+	if r.Tag.FullRange != nil {
+		fullRange = *r.Tag.FullRange
+	}
+
+	return protocol.DocumentSymbol{
+		Name:           r.Tag.Text,
+		Detail:         r.Tag.Detail,
+		Kind:           protocol.SymbolKind(r.Tag.Kind),
+		Deprecated:     r.Tag.Deprecated,
+		Range:          convertRange(fullRange),
+		SelectionRange: convertRange(r),
+	}, true
+}
+
+// toDumpURI translates a URI sent by the client into the URI space of the dump.
+func (s *server) toDumpURI(uri string) string {
+	if s.clientRoot != "" && s.dumpRoot != "" && strings.HasPrefix(uri, s.clientRoot) {
+		return s.dumpRoot + strings.TrimPrefix(uri, s.clientRoot)
+	}
+
+	return uri
+}
+
+// toClientURI translates a URI of the dump into the URI space of the client. Relative URIs
+// in the dump are resolved against the client's root.
+func (s *server) toClientURI(uri string) string {
+	root := s.clientRoot
+	if root == "" {
+		root = s.dumpRoot
+	}
+
+	if !strings.Contains(uri, "://") {
+		return root + strings.TrimPrefix(uri, "/")
+	}
+
+	if s.dumpRoot != "" && strings.HasPrefix(uri, s.dumpRoot) {
+		return root + strings.TrimPrefix(uri, s.dumpRoot)
+	}
+
+	return uri
+}
+
+func unmarshalParams(params json.RawMessage, v interface{}) error {
+	if len(params) == 0 {
+		return nil
+	}
+
+	if err := json.Unmarshal(params, v); err != nil {
+		return &rpcError{Code: codeInvalidParams, Message: err.Error()}
+	}
+
+	return nil
+}
+
+func convertRange(r reader.Range) protocol.RangeData {
+	return protocol.RangeData{
+		Start: protocol.Pos{Line: r.StartLine, Character: r.StartCharacter},
+		End:   protocol.Pos{Line: r.EndLine, Character: r.EndCharacter},
+	}
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sourcegraph/lsif-protocol/query"
+)
+
+var testDump = strings.Join([]string{
+	`{"id": 1, "type": "vertex", "label": "metaData", "version": "0.4.3", "projectRoot": "file:///tmp/build"}`,
+	`{"id": 2, "type": "vertex", "label": "document", "uri": "file:///tmp/build/foo.go"}`,
+	`{"id": 3, "type": "vertex", "label": "range", "start": {"line": 1, "character": 5}, "end": {"line": 1, "character": 8}, "tag": {"type": "definition", "text": "Foo", "kind": 12, "fullRange": {"start": {"line": 1, "character": 0}, "end": {"line": 3, "character": 1}}}}`,
+	`{"id": 4, "type": "vertex", "label": "range", "start": {"line": 5, "character": 1}, "end": {"line": 5, "character": 4}, "tag": {"type": "reference", "text": "Foo"}}`,
+	`{"id": 5, "type": "edge", "label": "contains", "outV": 2, "inVs": [3, 4]}`,
+	`{"id": 6, "type": "vertex", "label": "resultSet"}`,
+	`{"id": 7, "type": "edge", "label": "next", "outV": 3, "inV": 6}`,
+	`{"id": 8, "type": "edge", "label": "next", "outV": 4, "inV": 6}`,
+	`{"id": 9, "type": "vertex", "label": "hoverResult", "result": {"contents": "func Foo()"}}`,
+	`{"id": 10, "type": "edge", "label": "textDocument/hover", "outV": 6, "inV": 9}`,
+	`{"id": 11, "type": "vertex", "label": "definitionResult"}`,
+	`{"id": 12, "type": "edge", "label": "textDocument/definition", "outV": 6, "inV": 11}`,
+	`{"id": 13, "type": "edge", "label": "item", "outV": 11, "inVs": [3], "document": 2}`,
+}, "\n")
+
+func TestServe(t *testing.T) {
+	index, err := query.Load(context.Background(), strings.NewReader(testDump))
+	if err != nil {
+		t.Fatalf("unexpected error loading dump: %s", err)
+	}
+
+	var in bytes.Buffer
+	for _, message := range []string{
+		`{"jsonrpc": "2.0", "id": 1, "method": "initialize", "params": {"rootUri": "file:///home/me/repo"}}`,
+		`{"jsonrpc": "2.0", "method": "initialized", "params": {}}`,
+		`{"jsonrpc": "2.0", "id": 2, "method": "textDocument/definition", "params": {"textDocument": {"uri": "file:///home/me/repo/foo.go"}, "position": {"line": 5, "character": 2}}}`,
+		`{"jsonrpc": "2.0", "id": 3, "method": "textDocument/hover", "params": {"textDocument": {"uri": "file:///home/me/repo/foo.go"}, "position": {"line": 5, "character": 2}}}`,
+		`{"jsonrpc": "2.0", "id": 4, "method": "textDocument/documentSymbol", "params": {"textDocument": {"uri": "file:///home/me/repo/foo.go"}}}`,
+		`{"jsonrpc": "2.0", "id": 5, "method": "textDocument/rename", "params": {}}`,
+		`{"jsonrpc": "2.0", "id": 6, "method": "shutdown"}`,
+		`{"jsonrpc": "2.0", "method": "exit"}`,
+	} {
+		if err := writeMessage(&in, json.RawMessage(message)); err != nil {
+			t.Fatalf("unexpected error writing message: %s", err)
+		}
+	}
+
+	var out bytes.Buffer
+	if err := newServer(index).serve(&in, &out); err != nil {
+		t.Fatalf("unexpected error serving: %s", err)
+	}
+
+	var responses []string
+	r := bufio.NewReader(&out)
+	for {
+		body, err := readMessage(r)
+		if err != nil {
+			break
+		}
+
+		responses = append(responses, string(body))
+	}
+
+	expectedResponses := []string{
+		`{"jsonrpc":"2.0","id":1,"result":{"capabilities":{"definitionProvider":true,"documentSymbolProvider":true,"hoverProvider":true,"implementationProvider":true,"referencesProvider":true}}}`,
+		`{"jsonrpc":"2.0","id":2,"result":[{"uri":"file:///home/me/repo/foo.go","range":{"start":{"line":1,"character":5},"end":{"line":1,"character":8}}}]}`,
+		`{"jsonrpc":"2.0","id":3,"result":{"contents":{"kind":"markdown","value":"func Foo()"},"range":{"start":{"line":5,"character":1},"end":{"line":5,"character":4}}}}`,
+		`{"jsonrpc":"2.0","id":4,"result":[{"name":"Foo","kind":12,"range":{"start":{"line":1,"character":0},"end":{"line":3,"character":1}},"selectionRange":{"start":{"line":1,"character":5},"end":{"line":1,"character":8}}}]}`,
+		`{"jsonrpc":"2.0","id":5,"error":{"code":-32601,"message":"method not found: textDocument/rename"}}`,
+		`{"jsonrpc":"2.0","id":6,"result":null}`,
+	}
+	if diff := cmp.Diff(expectedResponses, responses); diff != "" {
+		t.Errorf("unexpected responses (-want +got):\n%s", diff)
+	}
+}
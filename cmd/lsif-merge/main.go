@@ -0,0 +1,77 @@
+// Command lsif-merge combines several LSIF dumps, such as those produced by sharded
+// indexing workers, into a single dump with non-colliding identifiers.
+//
+// Example:
+//
+//	lsif-merge -o dump.lsif shard1.lsif shard2.lsif shard3.lsif
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/sourcegraph/lsif-protocol/merge"
+	"github.com/sourcegraph/lsif-protocol/writer"
+)
+
+func main() {
+	output := flag.String("o", "", "file to write the merged dump to (default: stdout)")
+	compress := flag.Bool("gzip", false, "gzip-compress the merged dump")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s [flags] <dump.lsif>...\n\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err := run(flag.Args(), *output, *compress); err != nil {
+		fmt.Fprintf(os.Stderr, "lsif-merge: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(names []string, output string, compress bool) (err error) {
+	var readers []io.Reader
+	for _, name := range names {
+		f, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		readers = append(readers, f)
+	}
+
+	var out io.Writer = os.Stdout
+	if output != "" {
+		f, err := os.Create(output)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if closeErr := f.Close(); err == nil {
+				err = closeErr
+			}
+		}()
+		out = f
+	}
+
+	var options []writer.WriterOption
+	if compress {
+		options = append(options, writer.WithGzip())
+	}
+
+	w := writer.NewJSONWriter(out, options...)
+	if err := merge.Merge(context.Background(), w, readers...); err != nil {
+		_ = w.Flush()
+		return err
+	}
+
+	return w.Flush()
+}
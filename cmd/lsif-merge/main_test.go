@@ -0,0 +1,87 @@
+package main
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRun(t *testing.T) {
+	shard1 := writeDump(t, "shard1.lsif", []string{
+		`{"id":1,"type":"vertex","label":"metaData","version":"0.4.3","projectRoot":"file:///build"}`,
+		`{"id":2,"type":"vertex","label":"document","uri":"file:///build/a.go","languageId":"go"}`,
+	})
+	shard2 := writeDump(t, "shard2.lsif", []string{
+		`{"id":1,"type":"vertex","label":"metaData","version":"0.4.3","projectRoot":"file:///build"}`,
+		`{"id":2,"type":"vertex","label":"document","uri":"file:///build/b.go","languageId":"go"}`,
+	})
+
+	expected := strings.Join([]string{
+		`{"id":1,"type":"vertex","label":"metaData","version":"0.4.3","projectRoot":"file:///build"}`,
+		`{"id":2,"type":"vertex","label":"document","uri":"file:///build/a.go","languageId":"go"}`,
+		`{"id":3,"type":"vertex","label":"document","uri":"file:///build/b.go","languageId":"go"}`,
+	}, "\n") + "\n"
+
+	for _, compress := range []bool{false, true} {
+		output := filepath.Join(t.TempDir(), "merged.lsif")
+		if err := run([]string{shard1, shard2}, output, compress); err != nil {
+			t.Fatalf("unexpected error merging dumps: %s", err)
+		}
+
+		if diff := cmp.Diff(expected, readOutput(t, output, compress)); diff != "" {
+			t.Errorf("unexpected output with compress=%v (-want +got):\n%s", compress, diff)
+		}
+	}
+}
+
+func TestRunMissingDump(t *testing.T) {
+	dir := t.TempDir()
+	output := filepath.Join(dir, "merged.lsif")
+
+	if err := run([]string{filepath.Join(dir, "missing.lsif")}, output, false); err == nil {
+		t.Errorf("expected error merging missing dump")
+	}
+	if _, err := os.Stat(output); !os.IsNotExist(err) {
+		t.Errorf("expected no output to be written")
+	}
+}
+
+// writeDump writes the given lines to a dump file with the given name and returns its path.
+func writeDump(t *testing.T, name string, lines []string) string {
+	name = filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(name, []byte(strings.Join(lines, "\n")), 0644); err != nil {
+		t.Fatalf("unexpected error writing dump: %s", err)
+	}
+
+	return name
+}
+
+// readOutput returns the content of the given output file, decompressing it if necessary.
+func readOutput(t *testing.T, name string, compressed bool) string {
+	f, err := os.Open(name)
+	if err != nil {
+		t.Fatalf("unexpected error opening output: %s", err)
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+	if compressed {
+		gzipReader, err := gzip.NewReader(f)
+		if err != nil {
+			t.Fatalf("unexpected error reading compressed output: %s", err)
+		}
+		r = gzipReader
+	}
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("unexpected error reading output: %s", err)
+	}
+
+	return string(data)
+}
@@ -0,0 +1,82 @@
+// Command lsif-reroot moves the documents of an LSIF dump to a new root URI, for example to
+// replace the paths of a container build directory with the location of the repository. It
+// can also rewrite document URIs to be relative to the project root.
+//
+// Example:
+//
+//	lsif-reroot -to file:///home/me/repo -o rerooted.lsif dump.lsif
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/sourcegraph/lsif-protocol/transform"
+	"github.com/sourcegraph/lsif-protocol/writer"
+)
+
+func main() {
+	from := flag.String("from", "", "root URI of the documents in the dump (default: the projectRoot of the dump)")
+	to := flag.String("to", "", "new root URI of the documents, also written as the projectRoot")
+	relative := flag.Bool("relative", false, "write document URIs relative to the projectRoot")
+	output := flag.String("o", "", "file to write the rewritten dump to (default: stdout)")
+	compress := flag.Bool("gzip", false, "gzip-compress the rewritten dump")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s [flags] [dump.lsif]\n\nReads the dump from stdin if no file is given.\n\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() > 1 || (*to == "" && !*relative) {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	r := &rerooter{from: *from, to: *to, relative: *relative}
+	if err := run(flag.Arg(0), *output, *compress, r); err != nil {
+		fmt.Fprintf(os.Stderr, "lsif-reroot: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(name, output string, compress bool, r *rerooter) (err error) {
+	var in io.Reader = os.Stdin
+	if name != "" && name != "-" {
+		f, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+
+	var out io.Writer = os.Stdout
+	if output != "" {
+		f, err := os.Create(output)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if closeErr := f.Close(); err == nil {
+				err = closeErr
+			}
+		}()
+		out = f
+	}
+
+	var options []writer.WriterOption
+	if compress {
+		options = append(options, writer.WithGzip())
+	}
+
+	w := writer.NewJSONWriter(out, options...)
+	if err := transform.Run(context.Background(), in, w, r); err != nil {
+		_ = w.Flush()
+		return err
+	}
+
+	return w.Flush()
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/sourcegraph/lsif-protocol/reader"
+	"github.com/sourcegraph/lsif-protocol/transform"
+)
+
+// rerooter is a transformer that moves the documents of a dump from one root URI to another.
+type rerooter struct {
+	// from is the root URI of the input. If empty, the projectRoot of the metaData vertex is used.
+	from string
+
+	// to is the root URI of the output. If empty, the projectRoot is left unchanged.
+	to string
+
+	// relative causes document URIs under the root to be written relative to the projectRoot.
+	relative bool
+}
+
+func (r *rerooter) Transform(e *transform.Element) (bool, error) {
+	if e.Type != "vertex" {
+		return true, nil
+	}
+
+	switch e.Label {
+	case "metaData":
+		var projectRoot string
+		if _, err := e.Get("projectRoot", &projectRoot); err != nil {
+			return false, err
+		}
+
+		if r.from == "" {
+			r.from = projectRoot
+		}
+		if r.to != "" && r.to != projectRoot {
+			if err := e.Set("projectRoot", r.to); err != nil {
+				return false, err
+			}
+		}
+
+	case "document":
+		var uri string
+		if _, err := e.Get("uri", &uri); err != nil {
+			return false, err
+		}
+
+		if rerooted, ok := r.reroot(uri); ok && rerooted != uri {
+			if err := e.Set("uri", rerooted); err != nil {
+				return false, err
+			}
+		}
+	}
+
+	return true, nil
+}
+
+// reroot returns the given URI moved to the target root. It returns false if the URI is not
+// under the source root.
+func (r *rerooter) reroot(uri string) (string, bool) {
+	if r.from == "" {
+		return "", false
+	}
+
+	from := reader.WithTrailingSlash(r.from)
+	if !strings.HasPrefix(uri, from) {
+		return "", false
+	}
+
+	rel := strings.TrimPrefix(uri, from)
+	if r.relative {
+		return rel, true
+	}
+
+	to := r.to
+	if to == "" {
+		to = r.from
+	}
+
+	return reader.WithTrailingSlash(to) + rel, true
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sourcegraph/lsif-protocol/internal/lsiftest"
+	"github.com/sourcegraph/lsif-protocol/transform"
+	"github.com/sourcegraph/lsif-protocol/writer"
+)
+
+func TestReroot(t *testing.T) {
+	input := strings.Join([]string{
+		`{"id":1,"type":"vertex","label":"metaData","version":"0.4.3","projectRoot":"file:///tmp/build"}`,
+		`{"id":2,"type":"vertex","label":"document","uri":"file:///tmp/build/pkg/foo%20bar.go","languageId":"go"}`,
+		`{"id":3,"type":"vertex","label":"document","uri":"file:///usr/lib/go/src/fmt/print.go","languageId":"go"}`,
+		`{"id":4,"type":"vertex","label":"document","uri":"baz.go","languageId":"go"}`,
+	}, "\n")
+
+	testCases := []struct {
+		name     string
+		rerooter *rerooter
+		expected []string
+	}{
+		{
+			name:     "to",
+			rerooter: &rerooter{to: "file:///home/me/repo/"},
+			expected: []string{
+				`{"id":1,"type":"vertex","label":"metaData","version":"0.4.3","projectRoot":"file:///home/me/repo/"}`,
+				`{"id":2,"type":"vertex","label":"document","uri":"file:///home/me/repo/pkg/foo%20bar.go","languageId":"go"}`,
+				`{"id":3,"type":"vertex","label":"document","uri":"file:///usr/lib/go/src/fmt/print.go","languageId":"go"}`,
+				`{"id":4,"type":"vertex","label":"document","uri":"baz.go","languageId":"go"}`,
+			},
+		},
+		{
+			name:     "relative",
+			rerooter: &rerooter{relative: true},
+			expected: []string{
+				`{"id":1,"type":"vertex","label":"metaData","version":"0.4.3","projectRoot":"file:///tmp/build"}`,
+				`{"id":2,"type":"vertex","label":"document","uri":"pkg/foo%20bar.go","languageId":"go"}`,
+				`{"id":3,"type":"vertex","label":"document","uri":"file:///usr/lib/go/src/fmt/print.go","languageId":"go"}`,
+				`{"id":4,"type":"vertex","label":"document","uri":"baz.go","languageId":"go"}`,
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		output := lsiftest.Lines(t, func(w writer.JSONWriter) error {
+			return transform.Run(context.Background(), strings.NewReader(input), w, testCase.rerooter)
+		})
+		if diff := cmp.Diff(testCase.expected, output); diff != "" {
+			t.Errorf("unexpected output for %s (-want +got):\n%s", testCase.name, diff)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+// Command lsif-stats reports the composition of an LSIF dump: element counts and sizes by
+// label, the largest hover results and documents, moniker schemes, and edge fan-out.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+)
+
+func main() {
+	top := flag.Int("top", 10, "number of entries to report in ranked lists")
+	asJSON := flag.Bool("json", false, "output statistics as JSON")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s [flags] [dump.lsif]\n\nReads the dump from stdin if no file is given.\n\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() > 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err := run(flag.Arg(0), *top, *asJSON, os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "lsif-stats: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(name string, top int, asJSON bool, out io.Writer) error {
+	var r io.Reader = os.Stdin
+	if name != "" && name != "-" {
+		f, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	stats, err := collect(context.Background(), r, top)
+	if err != nil {
+		return err
+	}
+
+	if asJSON {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(stats)
+	}
+
+	return report(stats, out)
+}
+
+// report writes a human-readable representation of the given statistics.
+func report(stats *Stats, out io.Writer) error {
+	w := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+
+	fmt.Fprintf(w, "%d elements, %d bytes\n", stats.Elements, stats.Bytes)
+
+	fmt.Fprintf(w, "\nTYPE\tLABEL\tCOUNT\tBYTES\t%%\n")
+	for _, label := range stats.Labels {
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%.1f\n", label.Type, label.Label, label.Count, label.Bytes, percent(label.Bytes, stats.Bytes))
+	}
+
+	if len(stats.LargestHoverResults) > 0 {
+		fmt.Fprintf(w, "\nHOVER RESULT\tLINE\tBYTES\tPREVIEW\n")
+		for _, hover := range stats.LargestHoverResults {
+			fmt.Fprintf(w, "%d\t%d\t%d\t%s\n", hover.ID, hover.Line, hover.Bytes, hover.Preview)
+		}
+	}
+
+	if len(stats.LargestDocuments) > 0 {
+		fmt.Fprintf(w, "\nDOCUMENT\tRANGES\n")
+		for _, document := range stats.LargestDocuments {
+			fmt.Fprintf(w, "%s\t%d\n", document.URI, document.Ranges)
+		}
+	}
+
+	if len(stats.MonikerSchemes) > 0 {
+		fmt.Fprintf(w, "\nMONIKER SCHEME\tCOUNT\n")
+		for _, scheme := range stats.MonikerSchemes {
+			fmt.Fprintf(w, "%s\t%d\n", scheme.Scheme, scheme.Count)
+		}
+	}
+
+	fmt.Fprintf(w, "\nEDGE\tCOUNT\tINVS\tMAX\tAVERAGE\n")
+	for _, label := range fanOutLabels {
+		fanOut := stats.FanOut[label]
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%.2f\n", label, fanOut.Edges, fanOut.InVs, fanOut.Max, fanOut.Average)
+	}
+
+	return w.Flush()
+}
+
+func percent(n, total int64) float64 {
+	if total == 0 {
+		return 0
+	}
+
+	return float64(n) * 100 / float64(total)
+}
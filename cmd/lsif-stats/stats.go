@@ -0,0 +1,195 @@
+package main
+
+import (
+	"context"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/sourcegraph/lsif-protocol/reader"
+)
+
+// Stats summarizes the composition of an LSIF dump.
+type Stats struct {
+	Elements            int               `json:"elements"`
+	Bytes               int64             `json:"bytes"`
+	Labels              []LabelStats      `json:"labels"`
+	LargestHoverResults []HoverStats      `json:"largestHoverResults"`
+	LargestDocuments    []DocumentStats   `json:"largestDocuments"`
+	MonikerSchemes      []SchemeStats     `json:"monikerSchemes"`
+	FanOut              map[string]FanOut `json:"fanOut"`
+}
+
+// LabelStats counts the elements with a particular type and label.
+type LabelStats struct {
+	Type  string `json:"type"`
+	Label string `json:"label"`
+	Count int    `json:"count"`
+	Bytes int64  `json:"bytes"`
+}
+
+// HoverStats describes the size of a single hover result.
+type HoverStats struct {
+	ID      int    `json:"id"`
+	Line    int    `json:"line"`
+	Bytes   int    `json:"bytes"`
+	Preview string `json:"preview"`
+}
+
+// DocumentStats counts the ranges of a single document.
+type DocumentStats struct {
+	ID     int    `json:"id"`
+	URI    string `json:"uri"`
+	Ranges int    `json:"ranges"`
+}
+
+// SchemeStats counts the monikers with a particular scheme.
+type SchemeStats struct {
+	Scheme string `json:"scheme"`
+	Count  int    `json:"count"`
+}
+
+// FanOut describes the number of inVs of the edges with a particular label.
+type FanOut struct {
+	Edges   int     `json:"edges"`
+	InVs    int     `json:"inVs"`
+	Max     int     `json:"max"`
+	Average float64 `json:"average"`
+}
+
+// fanOutLabels are the labels of the edges whose fan-out is reported.
+var fanOutLabels = []string{"contains", "item"}
+
+// previewLength is the maximum number of characters of hover text included in a report.
+const previewLength = 60
+
+// collect reads the given dump and computes its statistics. At most top entries are
+// reported for each ranked list.
+func collect(ctx context.Context, r io.Reader, top int) (*Stats, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stats := &Stats{FanOut: map[string]FanOut{}}
+	labels := map[[2]string]*LabelStats{}
+	documents := map[int]*DocumentStats{}
+	schemes := map[string]int{}
+
+	for pair := range reader.Read(ctx, r) {
+		if pair.Err != nil {
+			return nil, pair.Err
+		}
+		element := pair.Element
+
+		stats.Elements++
+		stats.Bytes += int64(pair.Length)
+
+		key := [2]string{element.Type, element.Label}
+		if _, ok := labels[key]; !ok {
+			labels[key] = &LabelStats{Type: element.Type, Label: element.Label}
+		}
+		labels[key].Count++
+		labels[key].Bytes += int64(pair.Length)
+
+		switch payload := element.Payload.(type) {
+		case reader.Document:
+			documents[element.ID] = &DocumentStats{ID: element.ID, URI: payload.URI}
+
+		case string:
+			if element.Label == "hoverResult" {
+				stats.LargestHoverResults = append(stats.LargestHoverResults, HoverStats{
+					ID:      element.ID,
+					Line:    pair.Line,
+					Bytes:   pair.Length,
+					Preview: preview(payload),
+				})
+			}
+
+		case reader.Moniker:
+			schemes[payload.Scheme]++
+
+		case reader.Edge:
+			if element.Label == "contains" {
+				if document, ok := documents[payload.OutV]; ok {
+					document.Ranges += len(payload.InVs)
+				}
+			}
+
+			for _, label := range fanOutLabels {
+				if element.Label == label {
+					fanOut := stats.FanOut[label]
+					fanOut.Edges++
+					fanOut.InVs += len(payload.InVs)
+					if len(payload.InVs) > fanOut.Max {
+						fanOut.Max = len(payload.InVs)
+					}
+					stats.FanOut[label] = fanOut
+				}
+			}
+		}
+	}
+
+	for _, labelStats := range labels {
+		stats.Labels = append(stats.Labels, *labelStats)
+	}
+	sort.Slice(stats.Labels, func(i, j int) bool {
+		if stats.Labels[i].Bytes != stats.Labels[j].Bytes {
+			return stats.Labels[i].Bytes > stats.Labels[j].Bytes
+		}
+
+		return stats.Labels[i].Label < stats.Labels[j].Label
+	})
+
+	sort.SliceStable(stats.LargestHoverResults, func(i, j int) bool {
+		return stats.LargestHoverResults[i].Bytes > stats.LargestHoverResults[j].Bytes
+	})
+	if len(stats.LargestHoverResults) > top {
+		stats.LargestHoverResults = stats.LargestHoverResults[:top]
+	}
+
+	for _, document := range documents {
+		stats.LargestDocuments = append(stats.LargestDocuments, *document)
+	}
+	sort.Slice(stats.LargestDocuments, func(i, j int) bool {
+		if stats.LargestDocuments[i].Ranges != stats.LargestDocuments[j].Ranges {
+			return stats.LargestDocuments[i].Ranges > stats.LargestDocuments[j].Ranges
+		}
+
+		return stats.LargestDocuments[i].URI < stats.LargestDocuments[j].URI
+	})
+	if len(stats.LargestDocuments) > top {
+		stats.LargestDocuments = stats.LargestDocuments[:top]
+	}
+
+	for scheme, count := range schemes {
+		stats.MonikerSchemes = append(stats.MonikerSchemes, SchemeStats{Scheme: scheme, Count: count})
+	}
+	sort.Slice(stats.MonikerSchemes, func(i, j int) bool {
+		if stats.MonikerSchemes[i].Count != stats.MonikerSchemes[j].Count {
+			return stats.MonikerSchemes[i].Count > stats.MonikerSchemes[j].Count
+		}
+
+		return stats.MonikerSchemes[i].Scheme < stats.MonikerSchemes[j].Scheme
+	})
+
+	for label, fanOut := range stats.FanOut {
+		if fanOut.Edges > 0 {
+			fanOut.Average = float64(fanOut.InVs) / float64(fanOut.Edges)
+		}
+		stats.FanOut[label] = fanOut
+	}
+
+	return stats, nil
+}
+
+// preview returns the first line of the given text, truncated to a reasonable length.
+func preview(text string) string {
+	if i := strings.IndexByte(text, '\n'); i >= 0 {
+		text = text[:i]
+	}
+
+	if runes := []rune(text); len(runes) > previewLength {
+		text = string(runes[:previewLength]) + "..."
+	}
+
+	return text
+}
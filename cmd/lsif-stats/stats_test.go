@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCollect(t *testing.T) {
+	lines := []string{
+		`{"id":1,"type":"vertex","label":"metaData","version":"0.4.3","projectRoot":"file:///test"}`,
+		`{"id":2,"type":"vertex","label":"document","uri":"file:///test/foo.go"}`,
+		`{"id":3,"type":"vertex","label":"document","uri":"file:///test/bar.go"}`,
+		`{"id":4,"type":"vertex","label":"range","start":{"line":1,"character":2},"end":{"line":1,"character":5}}`,
+		`{"id":5,"type":"vertex","label":"range","start":{"line":2,"character":2},"end":{"line":2,"character":5}}`,
+		`{"id":6,"type":"vertex","label":"range","start":{"line":3,"character":2},"end":{"line":3,"character":5}}`,
+		`{"id":7,"type":"edge","label":"contains","outV":2,"inVs":[4,5]}`,
+		`{"id":8,"type":"edge","label":"contains","outV":3,"inVs":[6]}`,
+		`{"id":9,"type":"vertex","label":"hoverResult","result":{"contents":"short"}}`,
+		`{"id":10,"type":"vertex","label":"hoverResult","result":{"contents":"a much longer hover text\nwith a second line"}}`,
+		`{"id":11,"type":"vertex","label":"moniker","kind":"export","scheme":"gomod","identifier":"a"}`,
+		`{"id":12,"type":"vertex","label":"moniker","kind":"import","scheme":"gomod","identifier":"b"}`,
+		`{"id":13,"type":"vertex","label":"moniker","kind":"import","scheme":"npm","identifier":"c"}`,
+		`{"id":14,"type":"vertex","label":"definitionResult"}`,
+		`{"id":15,"type":"edge","label":"item","outV":14,"inVs":[4,5,6],"document":2}`,
+	}
+
+	stats, err := collect(context.Background(), strings.NewReader(strings.Join(lines, "\n")), 1)
+	if err != nil {
+		t.Fatalf("unexpected error collecting stats: %s", err)
+	}
+
+	var totalBytes int64
+	for _, line := range lines {
+		totalBytes += int64(len(line))
+	}
+	if stats.Elements != len(lines) || stats.Bytes != totalBytes {
+		t.Errorf("unexpected totals. want=(%d, %d) have=(%d, %d)", len(lines), totalBytes, stats.Elements, stats.Bytes)
+	}
+
+	counts := map[string]int{}
+	for _, label := range stats.Labels {
+		counts[label.Label] = label.Count
+	}
+	expectedCounts := map[string]int{
+		"metaData":         1,
+		"document":         2,
+		"range":            3,
+		"contains":         2,
+		"hoverResult":      2,
+		"moniker":          3,
+		"definitionResult": 1,
+		"item":             1,
+	}
+	if diff := cmp.Diff(expectedCounts, counts); diff != "" {
+		t.Errorf("unexpected label counts (-want +got):\n%s", diff)
+	}
+
+	expectedHovers := []HoverStats{{ID: 10, Line: 10, Bytes: len(lines[9]), Preview: "a much longer hover text"}}
+	if diff := cmp.Diff(expectedHovers, stats.LargestHoverResults); diff != "" {
+		t.Errorf("unexpected hover results (-want +got):\n%s", diff)
+	}
+
+	expectedDocuments := []DocumentStats{{ID: 2, URI: "file:///test/foo.go", Ranges: 2}}
+	if diff := cmp.Diff(expectedDocuments, stats.LargestDocuments); diff != "" {
+		t.Errorf("unexpected documents (-want +got):\n%s", diff)
+	}
+
+	expectedSchemes := []SchemeStats{{Scheme: "gomod", Count: 2}, {Scheme: "npm", Count: 1}}
+	if diff := cmp.Diff(expectedSchemes, stats.MonikerSchemes); diff != "" {
+		t.Errorf("unexpected moniker schemes (-want +got):\n%s", diff)
+	}
+
+	expectedFanOut := map[string]FanOut{
+		"contains": {Edges: 2, InVs: 3, Max: 2, Average: 1.5},
+		"item":     {Edges: 1, InVs: 3, Max: 3, Average: 3},
+	}
+	if diff := cmp.Diff(expectedFanOut, stats.FanOut); diff != "" {
+		t.Errorf("unexpected fan-out (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,94 @@
+// Command lsif-validate checks an LSIF dump for structural and semantic errors. It prints a
+// report of each violation and exits with a non-zero status if any were found.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/sourcegraph/lsif-protocol/validation"
+)
+
+// maxReportedErrors is the default number of errors printed before the report is truncated.
+const maxReportedErrors = 100
+
+func main() {
+	sourceDir := flag.String("source", "", "directory containing the indexed source; enables checking ranges against document bounds")
+	maxErrors := flag.Int("max-errors", maxReportedErrors, "maximum number of errors to print (0 for no limit)")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s [flags] [dump.lsif]\n\nReads the dump from stdin if no file is given.\n\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() > 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	name := "-"
+	if flag.NArg() == 1 {
+		name = flag.Arg(0)
+	}
+
+	n, err := run(name, *sourceDir, *maxErrors, os.Stdout)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "lsif-validate: %s\n", err)
+		os.Exit(2)
+	}
+	if n > 0 {
+		os.Exit(1)
+	}
+}
+
+// run validates the named dump and writes a report to the given writer. It returns the
+// number of errors found.
+func run(name, sourceDir string, maxErrors int, out io.Writer) (int, error) {
+	var r io.Reader = os.Stdin
+	if name != "-" {
+		f, err := os.Open(name)
+		if err != nil {
+			return 0, err
+		}
+		defer f.Close()
+		r = f
+	} else {
+		name = "<stdin>"
+	}
+
+	var options []validation.Option
+	if sourceDir != "" {
+		options = append(options, validation.WithSourceReader(func(path string) ([]byte, error) {
+			return os.ReadFile(filepath.Join(sourceDir, filepath.FromSlash(path)))
+		}))
+	}
+
+	errs, err := validation.Validate(context.Background(), r, options...)
+	if err != nil {
+		return 0, err
+	}
+
+	for i, e := range errs {
+		if maxErrors > 0 && i >= maxErrors {
+			fmt.Fprintf(out, "%s: ... and %d more\n", name, len(errs)-i)
+			break
+		}
+
+		fmt.Fprintf(out, "%s:%d: element %d: %s\n", name, e.Line, e.ElementID, e.Message)
+	}
+
+	switch len(errs) {
+	case 0:
+		fmt.Fprintf(out, "%s: no errors found\n", name)
+	case 1:
+		fmt.Fprintf(out, "%s: 1 error\n", name)
+	default:
+		fmt.Fprintf(out, "%s: %d errors\n", name, len(errs))
+	}
+
+	return len(errs), nil
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRun(t *testing.T) {
+	name := writeDump(t, []string{
+		`{"id": 1, "type": "vertex", "label": "metaData", "version": "0.4.3", "projectRoot": "file:///test"}`,
+		`{"id": 2, "type": "vertex", "label": "document", "uri": "file:///test/foo.go"}`,
+		`{"id": 3, "type": "vertex", "label": "range", "start": {"line": 0, "character": 5}, "end": {"line": 0, "character": 8}}`,
+		`{"id": 4, "type": "edge", "label": "contains", "outV": 2, "inVs": [3]}`,
+	})
+
+	var buf bytes.Buffer
+	n, err := run(name, "", maxReportedErrors, &buf)
+	if err != nil {
+		t.Fatalf("unexpected error running validation: %s", err)
+	}
+	if n != 0 {
+		t.Errorf("unexpected number of errors. want=%d have=%d", 0, n)
+	}
+	if diff := cmp.Diff(name+": no errors found\n", buf.String()); diff != "" {
+		t.Errorf("unexpected report (-want +got):\n%s", diff)
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	name := writeDump(t, []string{
+		`{"id": 1, "type": "vertex", "label": "metaData", "version": "0.4.3", "projectRoot": "file:///test"}`,
+		`{"id": 2, "type": "vertex", "label": "document", "uri": "file:///test/foo.go"}`,
+		`{"id": 3, "type": "vertex", "label": "range", "start": {"line": 0, "character": 5}, "end": {"line": 0, "character": 8}}`,
+		`{"id": 4, "type": "edge", "label": "contains", "outV": 2, "inVs": [3]}`,
+		`{"id": 5, "type": "edge", "label": "next", "outV": 3, "inV": 42}`,
+		`{"id": 6, "type": "edge", "label": "next", "outV": 3, "inV": 43}`,
+	})
+
+	sourceDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(sourceDir, "foo.go"), []byte("var x\n"), 0644); err != nil {
+		t.Fatalf("unexpected error writing source: %s", err)
+	}
+
+	var buf bytes.Buffer
+	n, err := run(name, sourceDir, 2, &buf)
+	if err != nil {
+		t.Fatalf("unexpected error running validation: %s", err)
+	}
+	if n != 3 {
+		t.Errorf("unexpected number of errors. want=%d have=%d", 3, n)
+	}
+
+	expected := strings.Join([]string{
+		name + ":3: element 3: range position 0:8 is outside of the document, whose line 0 has 5 characters",
+		name + ":5: element 5: dangling reference: inV 42 does not refer to any vertex",
+		name + ": ... and 1 more",
+		name + ": 3 errors",
+	}, "\n") + "\n"
+	if diff := cmp.Diff(expected, buf.String()); diff != "" {
+		t.Errorf("unexpected report (-want +got):\n%s", diff)
+	}
+}
+
+func TestRunMissingDump(t *testing.T) {
+	if _, err := run(filepath.Join(t.TempDir(), "missing.lsif"), "", maxReportedErrors, &bytes.Buffer{}); err == nil {
+		t.Errorf("expected error validating missing dump")
+	}
+}
+
+// writeDump writes the given lines to a dump file and returns its path.
+func writeDump(t *testing.T, lines []string) string {
+	name := filepath.Join(t.TempDir(), "dump.lsif")
+	if err := os.WriteFile(name, []byte(strings.Join(lines, "\n")), 0644); err != nil {
+		t.Fatalf("unexpected error writing dump: %s", err)
+	}
+
+	return name
+}
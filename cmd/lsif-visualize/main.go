@@ -0,0 +1,56 @@
+// Command lsif-visualize renders an LSIF dump, or the neighborhood of a single vertex within
+// it, as a Graphviz DOT graph.
+//
+// Example:
+//
+//	lsif-visualize -root 42 -hops 3 dump.lsif | dot -Tsvg > graph.svg
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/sourcegraph/lsif-protocol/visualization"
+)
+
+func main() {
+	root := flag.Int("root", 0, "identifier of a document, range, or other vertex to center the graph on (default: entire dump)")
+	hops := flag.Int("hops", 2, "maximum number of edges between the root and any rendered vertex")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s [flags] [dump.lsif]\n\nReads the dump from stdin if no file is given.\n\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() > 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err := run(flag.Arg(0), *root, *hops, os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "lsif-visualize: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(name string, root, hops int, out io.Writer) error {
+	var r io.Reader = os.Stdin
+	if name != "" && name != "-" {
+		f, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	var options []visualization.Option
+	if root != 0 {
+		options = append(options, visualization.WithNeighborhood(root, hops))
+	}
+
+	return visualization.Visualize(context.Background(), r, out, options...)
+}
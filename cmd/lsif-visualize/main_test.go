@@ -0,0 +1,60 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+var testDump = strings.Join([]string{
+	`{"id": 1, "type": "vertex", "label": "metaData", "version": "0.4.3", "projectRoot": "file:///test"}`,
+	`{"id": 2, "type": "vertex", "label": "document", "uri": "file:///test/foo.go"}`,
+	`{"id": 3, "type": "vertex", "label": "range", "start": {"line": 1, "character": 5}, "end": {"line": 1, "character": 8}}`,
+	`{"id": 4, "type": "edge", "label": "contains", "outV": 2, "inVs": [3]}`,
+}, "\n")
+
+func TestRun(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "dump.lsif")
+	if err := os.WriteFile(name, []byte(testDump), 0644); err != nil {
+		t.Fatalf("unexpected error writing dump: %s", err)
+	}
+
+	testCases := map[int]string{
+		0: `digraph lsif {
+	node [shape=box, fontname="monospace"];
+	1 [label="1: metaData\nfile:///test"];
+	2 [label="2: document\nfile:///test/foo.go"];
+	3 [label="3: range\n1:5-1:8"];
+	2 -> 3 [label="4: contains"];
+}
+`,
+		3: `digraph lsif {
+	node [shape=box, fontname="monospace"];
+	2 [label="2: document\nfile:///test/foo.go"];
+	3 [label="3: range\n1:5-1:8"];
+	2 -> 3 [label="4: contains"];
+}
+`,
+	}
+
+	for root, expected := range testCases {
+		var buf bytes.Buffer
+		if err := run(name, root, 1, &buf); err != nil {
+			t.Fatalf("unexpected error visualizing dump: %s", err)
+		}
+
+		if diff := cmp.Diff(expected, buf.String()); diff != "" {
+			t.Errorf("unexpected output for root %d (-want +got):\n%s", root, diff)
+		}
+	}
+}
+
+func TestRunMissingDump(t *testing.T) {
+	if err := run(filepath.Join(t.TempDir(), "missing.lsif"), 0, 1, &bytes.Buffer{}); err == nil {
+		t.Errorf("expected error visualizing missing dump")
+	}
+}
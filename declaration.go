@@ -0,0 +1,37 @@
+package protocol
+
+type DeclarationResult struct {
+	Vertex
+}
+
+func NewDeclarationResult(id uint64) DeclarationResult {
+	return DeclarationResult{
+		Vertex: Vertex{
+			Element: Element{
+				ID:   id,
+				Type: ElementVertex,
+			},
+			Label: VertexDeclarationResult,
+		},
+	}
+}
+
+type TextDocumentDeclaration struct {
+	Edge
+	OutV uint64 `json:"outV"`
+	InV  uint64 `json:"inV"`
+}
+
+func NewTextDocumentDeclaration(id, outV, inV uint64) TextDocumentDeclaration {
+	return TextDocumentDeclaration{
+		Edge: Edge{
+			Element: Element{
+				ID:   id,
+				Type: ElementEdge,
+			},
+			Label: EdgeTextDocumentDeclaration,
+		},
+		OutV: outV,
+		InV:  inV,
+	}
+}
@@ -0,0 +1,105 @@
+package protocol
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+)
+
+type DiagnosticResult struct {
+	Vertex
+	Result []Diagnostic `json:"result"`
+}
+
+type Diagnostic struct {
+	Range    RangeData          `json:"range"`
+	Severity DiagnosticSeverity `json:"severity,omitempty"`
+	Code     *DiagnosticCode    `json:"code,omitempty"`
+	Source   string             `json:"source,omitempty"`
+	Message  string             `json:"message"`
+}
+
+// DiagnosticCode is the code of a diagnostic, which may be either a number or a string.
+type DiagnosticCode struct {
+	// Value is the code, or the decimal form of a numeric code.
+	Value    string
+	IsNumber bool
+}
+
+func NewDiagnosticCode(code string) *DiagnosticCode {
+	return &DiagnosticCode{Value: code}
+}
+
+func NewNumericDiagnosticCode(code int) *DiagnosticCode {
+	return &DiagnosticCode{Value: strconv.Itoa(code), IsNumber: true}
+}
+
+func (c DiagnosticCode) MarshalJSON() ([]byte, error) {
+	if c.IsNumber {
+		if _, err := strconv.Atoi(c.Value); err != nil {
+			return nil, fmt.Errorf("invalid numeric diagnostic code %q", c.Value)
+		}
+
+		return []byte(c.Value), nil
+	}
+
+	return marshaller.Marshal(c.Value)
+}
+
+func (c *DiagnosticCode) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '"' {
+		*c = DiagnosticCode{}
+		return marshaller.Unmarshal(data, &c.Value)
+	}
+
+	var code int
+	if err := marshaller.Unmarshal(data, &code); err != nil {
+		return err
+	}
+
+	*c = *NewNumericDiagnosticCode(code)
+	return nil
+}
+
+type DiagnosticSeverity int
+
+const (
+	DiagnosticSeverityError       DiagnosticSeverity = 1
+	DiagnosticSeverityWarning     DiagnosticSeverity = 2
+	DiagnosticSeverityInformation DiagnosticSeverity = 3
+	DiagnosticSeverityHint        DiagnosticSeverity = 4
+)
+
+func NewDiagnosticResult(id uint64, result []Diagnostic) DiagnosticResult {
+	return DiagnosticResult{
+		Vertex: Vertex{
+			Element: Element{
+				ID:   id,
+				Type: ElementVertex,
+			},
+			Label: VertexDianosticResult,
+		},
+		Result: result,
+	}
+}
+
+type TextDocumentDiagnostic struct {
+	Edge
+	OutV uint64 `json:"outV"`
+	InV  uint64 `json:"inV"`
+}
+
+func NewTextDocumentDiagnostic(id, outV, inV uint64) TextDocumentDiagnostic {
+	return TextDocumentDiagnostic{
+		Edge: Edge{
+			Element: Element{
+				ID:   id,
+				Type: ElementEdge,
+			},
+			Label: EdgeTextDocumentDiagnostic,
+		},
+		OutV: outV,
+		InV:  inV,
+	}
+}
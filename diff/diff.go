@@ -0,0 +1,282 @@
+// Package diff compares the code intelligence data of two LSIF dumps. Comparisons are made
+// by document path and range position so that they are independent of element identifiers
+// and emission order.
+package diff
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/sourcegraph/lsif-protocol/reader"
+)
+
+// DocumentDiff describes the differences in a single document.
+type DocumentDiff struct {
+	// Path is the decoded path of the document relative to the project root of its dump.
+	Path string
+
+	// Status is "added" or "removed" if the document exists in only one of the dumps
+	// and "modified" otherwise.
+	Status string
+
+	AddedRanges   []string
+	RemovedRanges []string
+	Changes       []RangeChange
+}
+
+// RangeChange describes a difference in the data attached to a range that exists in both dumps.
+type RangeChange struct {
+	// Range is the position of the range, formatted as startLine:startCharacter-endLine:endCharacter.
+	Range string
+
+	// Field is one of "definitions", "references", "hover", or "monikers".
+	Field string
+
+	Removed []string
+	Added   []string
+}
+
+// rangeKey identifies a range by its position.
+type rangeKey struct {
+	startLine      int
+	startCharacter int
+	endLine        int
+	endCharacter   int
+}
+
+func (k rangeKey) String() string {
+	return fmt.Sprintf("%d:%d-%d:%d", k.startLine, k.startCharacter, k.endLine, k.endCharacter)
+}
+
+func (k rangeKey) less(other rangeKey) bool {
+	if k.startLine != other.startLine {
+		return k.startLine < other.startLine
+	}
+	if k.startCharacter != other.startCharacter {
+		return k.startCharacter < other.startCharacter
+	}
+	if k.endLine != other.endLine {
+		return k.endLine < other.endLine
+	}
+
+	return k.endCharacter < other.endCharacter
+}
+
+// rangeSummary is the ID-independent representation of the data attached to a range.
+type rangeSummary struct {
+	definitions []string
+	references  []string
+	hovers      []string
+	monikers    []string
+}
+
+// merge returns the union of the data attached to two ranges with the same position.
+func (s rangeSummary) merge(other rangeSummary) rangeSummary {
+	return rangeSummary{
+		definitions: uniqueSorted(append(s.definitions, other.definitions...)),
+		references:  uniqueSorted(append(s.references, other.references...)),
+		hovers:      uniqueSorted(append(s.hovers, other.hovers...)),
+		monikers:    uniqueSorted(append(s.monikers, other.monikers...)),
+	}
+}
+
+// Diff returns the differences between the old and new graphs, ordered by document path.
+func Diff(oldGraph, newGraph *reader.Graph) []DocumentDiff {
+	oldDocuments := summarize(oldGraph)
+	newDocuments := summarize(newGraph)
+
+	var paths []string
+	for path := range oldDocuments {
+		paths = append(paths, path)
+	}
+	for path := range newDocuments {
+		if _, ok := oldDocuments[path]; !ok {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+
+	var diffs []DocumentDiff
+	for _, path := range paths {
+		oldRanges, inOld := oldDocuments[path]
+		newRanges, inNew := newDocuments[path]
+
+		switch {
+		case !inOld:
+			diffs = append(diffs, DocumentDiff{Path: path, Status: "added", AddedRanges: formatKeys(sortedKeys(newRanges))})
+		case !inNew:
+			diffs = append(diffs, DocumentDiff{Path: path, Status: "removed", RemovedRanges: formatKeys(sortedKeys(oldRanges))})
+		default:
+			if d, ok := diffDocument(path, oldRanges, newRanges); ok {
+				diffs = append(diffs, d)
+			}
+		}
+	}
+
+	return diffs
+}
+
+func diffDocument(path string, oldRanges, newRanges map[rangeKey]rangeSummary) (DocumentDiff, bool) {
+	d := DocumentDiff{Path: path, Status: "modified"}
+
+	for _, key := range sortedKeys(oldRanges) {
+		if _, ok := newRanges[key]; !ok {
+			d.RemovedRanges = append(d.RemovedRanges, key.String())
+		}
+	}
+
+	for _, key := range sortedKeys(newRanges) {
+		newSummary := newRanges[key]
+		oldSummary, ok := oldRanges[key]
+		if !ok {
+			d.AddedRanges = append(d.AddedRanges, key.String())
+			continue
+		}
+
+		d.Changes = appendChange(d.Changes, key, "definitions", oldSummary.definitions, newSummary.definitions)
+		d.Changes = appendChange(d.Changes, key, "references", oldSummary.references, newSummary.references)
+		d.Changes = appendChange(d.Changes, key, "hover", oldSummary.hovers, newSummary.hovers)
+		d.Changes = appendChange(d.Changes, key, "monikers", oldSummary.monikers, newSummary.monikers)
+	}
+
+	return d, len(d.AddedRanges) > 0 || len(d.RemovedRanges) > 0 || len(d.Changes) > 0
+}
+
+// appendChange appends a change to the given slice if the given sorted sets differ.
+func appendChange(changes []RangeChange, key rangeKey, field string, oldValues, newValues []string) []RangeChange {
+	removed, added := difference(oldValues, newValues), difference(newValues, oldValues)
+	if len(removed) == 0 && len(added) == 0 {
+		return changes
+	}
+
+	return append(changes, RangeChange{Range: key.String(), Field: field, Removed: removed, Added: added})
+}
+
+// summarize returns the ranges of each document in the given graph keyed by document path
+// and range position.
+func summarize(g *reader.Graph) map[string]map[rangeKey]rangeSummary {
+	documents := map[string]map[rangeKey]rangeSummary{}
+
+	for _, documentID := range g.Documents() {
+		uri, _ := g.DocumentURI(documentID)
+
+		// Documents that share a path (e.g., duplicate document vertices) are combined
+		path := relativePath(g, uri)
+		ranges, ok := documents[path]
+		if !ok {
+			ranges = map[rangeKey]rangeSummary{}
+			documents[path] = ranges
+		}
+
+		for _, rangeID := range g.DocumentRanges(documentID) {
+			r, _ := g.Range(rangeID)
+			hover, _ := g.Hover(rangeID)
+
+			var monikers []string
+			for _, moniker := range g.Monikers(rangeID) {
+				monikers = append(monikers, fmt.Sprintf("%s:%s:%s", moniker.Kind, moniker.Scheme, moniker.Identifier))
+			}
+
+			summary := rangeSummary{
+				definitions: formatLocations(g, g.Definitions(rangeID)),
+				references:  formatLocations(g, g.References(rangeID)),
+				hovers:      nonEmpty(hover),
+				monikers:    uniqueSorted(monikers),
+			}
+
+			// Distinct ranges may share a position (e.g., a definition and a reference)
+			key := newRangeKey(r)
+			if existing, ok := ranges[key]; ok {
+				summary = existing.merge(summary)
+			}
+			ranges[key] = summary
+		}
+	}
+
+	return documents
+}
+
+func formatLocations(g *reader.Graph, locations []reader.Location) []string {
+	var formatted []string
+	for _, location := range locations {
+		formatted = append(formatted, relativePath(g, location.URI)+":"+newRangeKey(location.Range).String())
+	}
+
+	return uniqueSorted(formatted)
+}
+
+func newRangeKey(r reader.Range) rangeKey {
+	return rangeKey{
+		startLine:      r.StartLine,
+		startCharacter: r.StartCharacter,
+		endLine:        r.EndLine,
+		endCharacter:   r.EndCharacter,
+	}
+}
+
+// relativePath returns the decoded path of the given URI relative to the graph's project
+// root so that dumps of the same project indexed in different directories, or with absolute
+// and relative document URIs, can be compared. URIs outside of the project root are returned
+// unchanged.
+func relativePath(g *reader.Graph, uri string) string {
+	if path, ok := reader.RelativePath(g.MetaData.ProjectRoot, uri); ok {
+		return path
+	}
+
+	return uri
+}
+
+// difference returns the values of the sorted slice a that do not occur in the sorted slice b.
+func difference(a, b []string) []string {
+	var values []string
+	for _, value := range a {
+		if i := sort.SearchStrings(b, value); i == len(b) || b[i] != value {
+			values = append(values, value)
+		}
+	}
+
+	return values
+}
+
+func uniqueSorted(values []string) []string {
+	sort.Strings(values)
+
+	var unique []string
+	for i, value := range values {
+		if i == 0 || values[i-1] != value {
+			unique = append(unique, value)
+		}
+	}
+
+	return unique
+}
+
+func sortedKeys(m map[rangeKey]rangeSummary) []rangeKey {
+	keys := make([]rangeKey, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].less(keys[j])
+	})
+
+	return keys
+}
+
+func formatKeys(keys []rangeKey) []string {
+	var formatted []string
+	for _, key := range keys {
+		formatted = append(formatted, key.String())
+	}
+
+	return formatted
+}
+
+func nonEmpty(value string) []string {
+	if value == "" {
+		return nil
+	}
+
+	return []string{value}
+}
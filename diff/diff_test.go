@@ -0,0 +1,149 @@
+package diff
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sourcegraph/lsif-protocol/reader"
+)
+
+func TestDiff(t *testing.T) {
+	oldGraph := correlate(t, []string{
+		`{"id": 1, "type": "vertex", "label": "metaData", "version": "0.4.3", "projectRoot": "file:///build/a"}`,
+		`{"id": 2, "type": "vertex", "label": "document", "uri": "file:///build/a/foo.go"}`,
+		`{"id": 3, "type": "vertex", "label": "document", "uri": "file:///build/a/old.go"}`,
+		`{"id": 4, "type": "vertex", "label": "range", "start": {"line": 1, "character": 5}, "end": {"line": 1, "character": 8}}`,
+		`{"id": 5, "type": "vertex", "label": "range", "start": {"line": 4, "character": 2}, "end": {"line": 4, "character": 5}}`,
+		`{"id": 6, "type": "vertex", "label": "range", "start": {"line": 9, "character": 2}, "end": {"line": 9, "character": 5}}`,
+		`{"id": 7, "type": "edge", "label": "contains", "outV": 2, "inVs": [4, 5, 6]}`,
+		`{"id": 8, "type": "vertex", "label": "hoverResult", "result": {"contents": "func Foo()"}}`,
+		`{"id": 9, "type": "edge", "label": "textDocument/hover", "outV": 4, "inV": 8}`,
+		`{"id": 10, "type": "vertex", "label": "definitionResult"}`,
+		`{"id": 11, "type": "edge", "label": "textDocument/definition", "outV": 5, "inV": 10}`,
+		`{"id": 12, "type": "edge", "label": "item", "outV": 10, "inVs": [4], "document": 2}`,
+	})
+
+	// Same data with renumbered identifiers, a different project root, and a different emission order
+	newGraph := correlate(t, []string{
+		`{"id": 100, "type": "vertex", "label": "metaData", "version": "0.4.3", "projectRoot": "file:///build/b"}`,
+		`{"id": 101, "type": "vertex", "label": "document", "uri": "file:///build/b/foo.go"}`,
+		`{"id": 102, "type": "vertex", "label": "document", "uri": "file:///build/b/new.go"}`,
+		`{"id": 103, "type": "vertex", "label": "range", "start": {"line": 4, "character": 2}, "end": {"line": 4, "character": 5}}`,
+		`{"id": 104, "type": "vertex", "label": "range", "start": {"line": 1, "character": 5}, "end": {"line": 1, "character": 8}}`,
+		`{"id": 105, "type": "vertex", "label": "range", "start": {"line": 7, "character": 0}, "end": {"line": 7, "character": 3}}`,
+		`{"id": 106, "type": "edge", "label": "contains", "outV": 101, "inVs": [103, 104, 105]}`,
+		`{"id": 107, "type": "vertex", "label": "hoverResult", "result": {"contents": "func Foo() error"}}`,
+		`{"id": 108, "type": "edge", "label": "textDocument/hover", "outV": 104, "inV": 107}`,
+		`{"id": 109, "type": "vertex", "label": "definitionResult"}`,
+		`{"id": 110, "type": "edge", "label": "textDocument/definition", "outV": 103, "inV": 109}`,
+		`{"id": 111, "type": "edge", "label": "item", "outV": 109, "inVs": [105], "document": 101}`,
+	})
+
+	expected := []DocumentDiff{
+		{
+			Path:          "foo.go",
+			Status:        "modified",
+			RemovedRanges: []string{"9:2-9:5"},
+			AddedRanges:   []string{"7:0-7:3"},
+			Changes: []RangeChange{
+				{Range: "1:5-1:8", Field: "hover", Removed: []string{"func Foo()"}, Added: []string{"func Foo() error"}},
+				{Range: "4:2-4:5", Field: "definitions", Removed: []string{"foo.go:1:5-1:8"}, Added: []string{"foo.go:7:0-7:3"}},
+			},
+		},
+		{Path: "new.go", Status: "added"},
+		{Path: "old.go", Status: "removed"},
+	}
+	if diff := cmp.Diff(expected, Diff(oldGraph, newGraph)); diff != "" {
+		t.Errorf("unexpected diff (-want +got):\n%s", diff)
+	}
+
+	if diffs := Diff(oldGraph, oldGraph); len(diffs) != 0 {
+		t.Errorf("unexpected differences comparing a dump to itself: %v", diffs)
+	}
+}
+
+func TestDiffRangesWithSamePosition(t *testing.T) {
+	oldGraph := correlate(t, []string{
+		`{"id": 1, "type": "vertex", "label": "metaData", "version": "0.4.3", "projectRoot": "file:///build"}`,
+		`{"id": 2, "type": "vertex", "label": "document", "uri": "file:///build/foo.go"}`,
+		`{"id": 3, "type": "vertex", "label": "range", "start": {"line": 1, "character": 5}, "end": {"line": 1, "character": 8}}`,
+		`{"id": 4, "type": "vertex", "label": "range", "start": {"line": 1, "character": 5}, "end": {"line": 1, "character": 8}}`,
+		`{"id": 5, "type": "edge", "label": "contains", "outV": 2, "inVs": [3, 4]}`,
+		`{"id": 6, "type": "vertex", "label": "definitionResult"}`,
+		`{"id": 7, "type": "edge", "label": "textDocument/definition", "outV": 3, "inV": 6}`,
+		`{"id": 8, "type": "edge", "label": "item", "outV": 6, "inVs": [3], "document": 2}`,
+		`{"id": 9, "type": "vertex", "label": "hoverResult", "result": {"contents": "func Foo()"}}`,
+		`{"id": 10, "type": "edge", "label": "textDocument/hover", "outV": 4, "inV": 9}`,
+	})
+
+	// The definition is lost from the first range and the hover text of the second changes
+	newGraph := correlate(t, []string{
+		`{"id": 1, "type": "vertex", "label": "metaData", "version": "0.4.3", "projectRoot": "file:///build"}`,
+		`{"id": 2, "type": "vertex", "label": "document", "uri": "file:///build/foo.go"}`,
+		`{"id": 3, "type": "vertex", "label": "range", "start": {"line": 1, "character": 5}, "end": {"line": 1, "character": 8}}`,
+		`{"id": 4, "type": "vertex", "label": "range", "start": {"line": 1, "character": 5}, "end": {"line": 1, "character": 8}}`,
+		`{"id": 5, "type": "edge", "label": "contains", "outV": 2, "inVs": [3, 4]}`,
+		`{"id": 9, "type": "vertex", "label": "hoverResult", "result": {"contents": "func Foo() error"}}`,
+		`{"id": 10, "type": "edge", "label": "textDocument/hover", "outV": 4, "inV": 9}`,
+	})
+
+	expected := []DocumentDiff{
+		{
+			Path:   "foo.go",
+			Status: "modified",
+			Changes: []RangeChange{
+				{Range: "1:5-1:8", Field: "definitions", Removed: []string{"foo.go:1:5-1:8"}},
+				{Range: "1:5-1:8", Field: "hover", Removed: []string{"func Foo()"}, Added: []string{"func Foo() error"}},
+			},
+		},
+	}
+	if diff := cmp.Diff(expected, Diff(oldGraph, newGraph)); diff != "" {
+		t.Errorf("unexpected diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestDiffNormalizesPaths(t *testing.T) {
+	absoluteGraph := correlate(t, []string{
+		`{"id": 1, "type": "vertex", "label": "metaData", "version": "0.4.3", "projectRoot": "file:///build/a"}`,
+		`{"id": 2, "type": "vertex", "label": "document", "uri": "file:///build/a/foo%20bar.go"}`,
+		`{"id": 3, "type": "vertex", "label": "range", "start": {"line": 1, "character": 5}, "end": {"line": 1, "character": 8}}`,
+		`{"id": 4, "type": "edge", "label": "contains", "outV": 2, "inVs": [3]}`,
+	})
+
+	relativeGraph := correlate(t, []string{
+		`{"id": 1, "type": "vertex", "label": "metaData", "version": "0.4.3", "projectRoot": "file:///build/b/"}`,
+		`{"id": 2, "type": "vertex", "label": "document", "uri": "foo%20bar.go"}`,
+		`{"id": 3, "type": "vertex", "label": "range", "start": {"line": 1, "character": 5}, "end": {"line": 1, "character": 8}}`,
+		`{"id": 4, "type": "edge", "label": "contains", "outV": 2, "inVs": [3]}`,
+		`{"id": 5, "type": "vertex", "label": "document", "uri": "baz.go"}`,
+	})
+
+	expected := []DocumentDiff{
+		{Path: "baz.go", Status: "added"},
+	}
+	if diff := cmp.Diff(expected, Diff(absoluteGraph, relativeGraph)); diff != "" {
+		t.Errorf("unexpected diff (-want +got):\n%s", diff)
+	}
+
+	emptyGraph := correlate(t, []string{
+		`{"id": 1, "type": "vertex", "label": "metaData", "version": "0.4.3", "projectRoot": "file:///build/c"}`,
+	})
+
+	expected = []DocumentDiff{
+		{Path: "foo bar.go", Status: "removed", RemovedRanges: []string{"1:5-1:8"}},
+	}
+	if diff := cmp.Diff(expected, Diff(absoluteGraph, emptyGraph)); diff != "" {
+		t.Errorf("unexpected diff (-want +got):\n%s", diff)
+	}
+}
+
+func correlate(t *testing.T, lines []string) *reader.Graph {
+	g, err := reader.Correlate(context.Background(), strings.NewReader(strings.Join(lines, "\n")))
+	if err != nil {
+		t.Fatalf("unexpected error correlating dump: %s", err)
+	}
+
+	return g
+}
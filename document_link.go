@@ -0,0 +1,45 @@
+package protocol
+
+type DocumentLinkResult struct {
+	Vertex
+	Result []DocumentLink `json:"result"`
+}
+
+type DocumentLink struct {
+	Range   RangeData `json:"range"`
+	Target  string    `json:"target,omitempty"`
+	Tooltip string    `json:"tooltip,omitempty"`
+}
+
+func NewDocumentLinkResult(id uint64, result []DocumentLink) DocumentLinkResult {
+	return DocumentLinkResult{
+		Vertex: Vertex{
+			Element: Element{
+				ID:   id,
+				Type: ElementVertex,
+			},
+			Label: VertexDocumentLinkResult,
+		},
+		Result: result,
+	}
+}
+
+type TextDocumentDocumentLink struct {
+	Edge
+	OutV uint64 `json:"outV"`
+	InV  uint64 `json:"inV"`
+}
+
+func NewTextDocumentDocumentLink(id, outV, inV uint64) TextDocumentDocumentLink {
+	return TextDocumentDocumentLink{
+		Edge: Edge{
+			Element: Element{
+				ID:   id,
+				Type: ElementEdge,
+			},
+			Label: EdgeTextDocumentDocumentLink,
+		},
+		OutV: outV,
+		InV:  inV,
+	}
+}
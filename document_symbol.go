@@ -0,0 +1,115 @@
+package protocol
+
+// DocumentSymbolResult is a documentSymbolResult vertex whose payload contains
+// full LSP document symbols.
+type DocumentSymbolResult struct {
+	Vertex
+	Result []DocumentSymbol `json:"result"`
+}
+
+type DocumentSymbol struct {
+	Name           string           `json:"name"`
+	Detail         string           `json:"detail,omitempty"`
+	Kind           SymbolKind       `json:"kind"`
+	Tags           []SymbolTag      `json:"tags,omitempty"`
+	Deprecated     bool             `json:"deprecated,omitempty"`
+	Range          RangeData        `json:"range"`
+	SelectionRange RangeData        `json:"selectionRange"`
+	Children       []DocumentSymbol `json:"children,omitempty"`
+}
+
+func NewDocumentSymbolResult(id uint64, result []DocumentSymbol) DocumentSymbolResult {
+	return DocumentSymbolResult{
+		Vertex: Vertex{
+			Element: Element{
+				ID:   id,
+				Type: ElementVertex,
+			},
+			Label: VertexDocumentSymbolResult,
+		},
+		Result: result,
+	}
+}
+
+// RangeBasedDocumentSymbolResult is a documentSymbolResult vertex whose payload
+// refers to range vertices emitted elsewhere in the dump instead of repeating
+// the symbol data inline.
+type RangeBasedDocumentSymbolResult struct {
+	Vertex
+	Result []RangeBasedDocumentSymbol `json:"result"`
+}
+
+type RangeBasedDocumentSymbol struct {
+	ID       uint64                     `json:"id"`
+	Children []RangeBasedDocumentSymbol `json:"children,omitempty"`
+}
+
+func NewRangeBasedDocumentSymbolResult(id uint64, result []RangeBasedDocumentSymbol) RangeBasedDocumentSymbolResult {
+	return RangeBasedDocumentSymbolResult{
+		Vertex: Vertex{
+			Element: Element{
+				ID:   id,
+				Type: ElementVertex,
+			},
+			Label: VertexDocumentSymbolResult,
+		},
+		Result: result,
+	}
+}
+
+type TextDocumentDocumentSymbol struct {
+	Edge
+	OutV uint64 `json:"outV"`
+	InV  uint64 `json:"inV"`
+}
+
+func NewTextDocumentDocumentSymbol(id, outV, inV uint64) TextDocumentDocumentSymbol {
+	return TextDocumentDocumentSymbol{
+		Edge: Edge{
+			Element: Element{
+				ID:   id,
+				Type: ElementEdge,
+			},
+			Label: EdgeTextDocumentDocumentSymbol,
+		},
+		OutV: outV,
+		InV:  inV,
+	}
+}
+
+type SymbolKind int
+
+const (
+	SymbolKindFile          SymbolKind = 1
+	SymbolKindModule        SymbolKind = 2
+	SymbolKindNamespace     SymbolKind = 3
+	SymbolKindPackage       SymbolKind = 4
+	SymbolKindClass         SymbolKind = 5
+	SymbolKindMethod        SymbolKind = 6
+	SymbolKindProperty      SymbolKind = 7
+	SymbolKindField         SymbolKind = 8
+	SymbolKindConstructor   SymbolKind = 9
+	SymbolKindEnum          SymbolKind = 10
+	SymbolKindInterface     SymbolKind = 11
+	SymbolKindFunction      SymbolKind = 12
+	SymbolKindVariable      SymbolKind = 13
+	SymbolKindConstant      SymbolKind = 14
+	SymbolKindString        SymbolKind = 15
+	SymbolKindNumber        SymbolKind = 16
+	SymbolKindBoolean       SymbolKind = 17
+	SymbolKindArray         SymbolKind = 18
+	SymbolKindObject        SymbolKind = 19
+	SymbolKindKey           SymbolKind = 20
+	SymbolKindNull          SymbolKind = 21
+	SymbolKindEnumMember    SymbolKind = 22
+	SymbolKindStruct        SymbolKind = 23
+	SymbolKindEvent         SymbolKind = 24
+	SymbolKindOperator      SymbolKind = 25
+	SymbolKindTypeParameter SymbolKind = 26
+)
+
+type SymbolTag int
+
+const (
+	SymbolTagDeprecated SymbolTag = 1
+)
@@ -38,6 +38,7 @@ const (
 	VertexHoverResult          VertexLabel = "hoverResult"
 	VertexReferenceResult      VertexLabel = "referenceResult"
 	VertexImplementationResult VertexLabel = "implementationResult"
+	VertexEvent                VertexLabel = "$event"
 )
 
 type Edge struct {
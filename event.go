@@ -0,0 +1,39 @@
+package protocol
+
+// Event marks the beginning or end of the data belonging to a project or
+// document. Consumers can use events to process a dump incrementally.
+type Event struct {
+	Vertex
+	Kind  EventKind  `json:"kind"`
+	Scope EventScope `json:"scope"`
+	Data  uint64     `json:"data"`
+}
+
+type EventKind string
+
+const (
+	EventKindBegin EventKind = "begin"
+	EventKindEnd   EventKind = "end"
+)
+
+type EventScope string
+
+const (
+	EventScopeProject  EventScope = "project"
+	EventScopeDocument EventScope = "document"
+)
+
+func NewEvent(id uint64, kind EventKind, scope EventScope, data uint64) Event {
+	return Event{
+		Vertex: Vertex{
+			Element: Element{
+				ID:   id,
+				Type: ElementVertex,
+			},
+			Label: VertexEvent,
+		},
+		Kind:  kind,
+		Scope: scope,
+		Data:  data,
+	}
+}
@@ -0,0 +1,58 @@
+package protocol
+
+type FoldingRangeResult struct {
+	Vertex
+	Result []FoldingRange `json:"result"`
+}
+
+// FoldingRange is an LSP folding range. The start and end characters are
+// optional: when absent, the range covers the remainder of the start line
+// and the entirety of the end line, respectively.
+type FoldingRange struct {
+	StartLine      int              `json:"startLine"`
+	StartCharacter *int             `json:"startCharacter,omitempty"`
+	EndLine        int              `json:"endLine"`
+	EndCharacter   *int             `json:"endCharacter,omitempty"`
+	Kind           FoldingRangeKind `json:"kind,omitempty"`
+}
+
+type FoldingRangeKind string
+
+const (
+	FoldingRangeKindComment FoldingRangeKind = "comment"
+	FoldingRangeKindImports FoldingRangeKind = "imports"
+	FoldingRangeKindRegion  FoldingRangeKind = "region"
+)
+
+func NewFoldingRangeResult(id uint64, result []FoldingRange) FoldingRangeResult {
+	return FoldingRangeResult{
+		Vertex: Vertex{
+			Element: Element{
+				ID:   id,
+				Type: ElementVertex,
+			},
+			Label: VertexFoldingRangeResult,
+		},
+		Result: result,
+	}
+}
+
+type TextDocumentFoldingRange struct {
+	Edge
+	OutV uint64 `json:"outV"`
+	InV  uint64 `json:"inV"`
+}
+
+func NewTextDocumentFoldingRange(id, outV, inV uint64) TextDocumentFoldingRange {
+	return TextDocumentFoldingRange{
+		Edge: Edge{
+			Element: Element{
+				ID:   id,
+				Type: ElementEdge,
+			},
+			Label: EdgeTextDocumentFoldingRange,
+		},
+		OutV: outV,
+		InV:  inV,
+	}
+}
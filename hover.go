@@ -1,6 +1,10 @@
 package protocol
 
-import jsoniter "github.com/json-iterator/go"
+import (
+	"bytes"
+
+	jsoniter "github.com/json-iterator/go"
+)
 
 type HoverResult struct {
 	Vertex
@@ -76,3 +80,25 @@ func NewTextDocumentHover(id, outV, inV uint64) TextDocumentHover {
 		InV:  inV,
 	}
 }
+
+// UnmarshalJSON decodes either a plain string, which is preserved as a raw marked
+// string, or an object with language and value properties.
+func (m *MarkedString) UnmarshalJSON(data []byte) error {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '"' {
+		var s string
+		if err := marshaller.Unmarshal(trimmed, &s); err != nil {
+			return err
+		}
+
+		*m = RawMarkedString(s)
+		return nil
+	}
+
+	var v markedString
+	if err := marshaller.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	*m = MarkedString(v)
+	return nil
+}
@@ -0,0 +1,127 @@
+package protocol
+
+import (
+	"reflect"
+	"strconv"
+	"unsafe"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// idFields are the JSON properties of elements whose values are element identifiers.
+var idFields = map[string]struct{}{
+	"id":       {},
+	"outV":     {},
+	"inV":      {},
+	"inVs":     {},
+	"document": {},
+	"data":     {},
+}
+
+var (
+	idType      = reflect.TypeOf(uint64(0))
+	idSliceType = reflect.TypeOf([]uint64(nil))
+)
+
+// StringIDEncoder serializes elements with their identifiers, and the identifiers of
+// the elements they refer to, written as JSON strings instead of numbers. Each identifier
+// is written as the decimal form of its value preceded by a fixed prefix.
+type StringIDEncoder struct {
+	api jsoniter.API
+}
+
+// NewStringIDEncoder creates a StringIDEncoder that prefixes each identifier with the
+// given value (e.g., a UUID unique to the producer of the dump).
+func NewStringIDEncoder(prefix string) *StringIDEncoder {
+	api := jsoniter.Config{
+		EscapeHTML:                    false,
+		MarshalFloatWith6Digits:       true,
+		ObjectFieldMustBeSimpleString: true,
+	}.Froze()
+	api.RegisterExtension(&stringIDExtension{prefix: prefix})
+	return &StringIDEncoder{api: api}
+}
+
+// Marshal returns the JSON encoding of the given element.
+func (e *StringIDEncoder) Marshal(element interface{}) ([]byte, error) {
+	return e.api.Marshal(element)
+}
+
+// Wrap returns a value whose JSON encoding is the encoding of the given element
+// produced by Marshal.
+func (e *StringIDEncoder) Wrap(element interface{}) interface{} {
+	return stringIDElement{encoder: e, element: element}
+}
+
+type stringIDElement struct {
+	encoder *StringIDEncoder
+	element interface{}
+}
+
+func (e stringIDElement) MarshalJSON() ([]byte, error) {
+	return e.encoder.Marshal(e.element)
+}
+
+type stringIDExtension struct {
+	jsoniter.DummyExtension
+	prefix string
+}
+
+func (x *stringIDExtension) UpdateStructDescriptor(structDescriptor *jsoniter.StructDescriptor) {
+	for _, binding := range structDescriptor.Fields {
+		if len(binding.ToNames) != 1 {
+			continue
+		}
+		if _, ok := idFields[binding.ToNames[0]]; !ok {
+			continue
+		}
+
+		switch binding.Field.Type().Type1() {
+		case idType:
+			binding.Encoder = &stringIDEncoder{prefix: x.prefix}
+		case idSliceType:
+			binding.Encoder = &stringIDSliceEncoder{prefix: x.prefix}
+		}
+	}
+}
+
+type stringIDEncoder struct {
+	prefix string
+}
+
+func (e *stringIDEncoder) IsEmpty(ptr unsafe.Pointer) bool {
+	return false
+}
+
+func (e *stringIDEncoder) Encode(ptr unsafe.Pointer, stream *jsoniter.Stream) {
+	writeStringID(stream, e.prefix, *(*uint64)(ptr))
+}
+
+type stringIDSliceEncoder struct {
+	prefix string
+}
+
+func (e *stringIDSliceEncoder) IsEmpty(ptr unsafe.Pointer) bool {
+	return len(*(*[]uint64)(ptr)) == 0
+}
+
+func (e *stringIDSliceEncoder) Encode(ptr unsafe.Pointer, stream *jsoniter.Stream) {
+	ids := *(*[]uint64)(ptr)
+	if ids == nil {
+		stream.WriteNil()
+		return
+	}
+
+	stream.WriteArrayStart()
+	for i, id := range ids {
+		if i > 0 {
+			stream.WriteMore()
+		}
+		writeStringID(stream, e.prefix, id)
+	}
+	stream.WriteArrayEnd()
+}
+
+func writeStringID(stream *jsoniter.Stream, prefix string, id uint64) {
+	stream.WriteString(prefix + strconv.FormatUint(id, 10))
+}
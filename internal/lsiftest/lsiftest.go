@@ -0,0 +1,32 @@
+// Package lsiftest provides helpers for tests of packages that write LSIF dumps.
+package lsiftest
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/sourcegraph/lsif-protocol/writer"
+)
+
+// Lines calls the given function with a JSONWriter backed by an in-memory buffer, flushes
+// the writer, and returns the lines written to it. The test fails if the function or the
+// flush returns an error.
+func Lines(t testing.TB, f func(w writer.JSONWriter) error) []string {
+	t.Helper()
+
+	var buf bytes.Buffer
+	w := writer.NewJSONWriter(&buf)
+	if err := f(w); err != nil {
+		t.Fatalf("unexpected error writing dump: %s", err)
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatalf("unexpected error flushing writer: %s", err)
+	}
+
+	if buf.Len() == 0 {
+		return nil
+	}
+
+	return strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+}
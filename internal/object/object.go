@@ -0,0 +1,117 @@
+// Package object provides an order-preserving representation of JSON objects that allows
+// individual properties of an LSIF element to be rewritten without disturbing the rest
+// of its encoding.
+package object
+
+import (
+	"bytes"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+var api = jsoniter.ConfigFastest
+
+// field is a property of a JSON object along with its raw value.
+type field struct {
+	key   string
+	value []byte
+}
+
+// Object is a JSON object whose properties are kept in their original order so that
+// rewritten elements differ from their input only in the rewritten values.
+type Object []field
+
+// Parse decodes the given JSON object. Property values are copied and do not alias the
+// given line.
+func Parse(line []byte) (Object, error) {
+	iter := api.BorrowIterator(line)
+	defer api.ReturnIterator(iter)
+
+	var o Object
+	iter.ReadObjectCB(func(iter *jsoniter.Iterator, key string) bool {
+		value := iter.SkipAndReturnBytes()
+		o = append(o, field{key: key, value: append([]byte(nil), bytes.TrimSpace(value)...)})
+		return true
+	})
+	if iter.Error != nil {
+		return nil, iter.Error
+	}
+
+	return o, nil
+}
+
+// Get returns the raw value of the given property.
+func (o Object) Get(key string) ([]byte, bool) {
+	for _, f := range o {
+		if f.key == key {
+			return f.value, true
+		}
+	}
+
+	return nil, false
+}
+
+// GetString returns the value of the given string property, or the empty string if the
+// property is absent or is not a string.
+func (o Object) GetString(key string) string {
+	raw, ok := o.Get(key)
+	if !ok {
+		return ""
+	}
+
+	var s string
+	_ = api.Unmarshal(raw, &s)
+	return s
+}
+
+// Unmarshal decodes the value of the given property into v. It returns false if the
+// property is absent.
+func (o Object) Unmarshal(key string, v interface{}) (bool, error) {
+	raw, ok := o.Get(key)
+	if !ok {
+		return false, nil
+	}
+
+	return true, api.Unmarshal(raw, v)
+}
+
+// Set replaces the raw value of the given property, appending the property if it is absent.
+func (o *Object) Set(key string, value []byte) {
+	for i, f := range *o {
+		if f.key == key {
+			(*o)[i].value = value
+			return
+		}
+	}
+
+	*o = append(*o, field{key: key, value: value})
+}
+
+// Delete removes the given property.
+func (o *Object) Delete(key string) {
+	for i, f := range *o {
+		if f.key == key {
+			*o = append((*o)[:i], (*o)[i+1:]...)
+			return
+		}
+	}
+}
+
+// Marshal returns the JSON encoding of the object.
+func (o Object) Marshal() []byte {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, f := range o {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		key, _ := api.Marshal(f.key)
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(f.value)
+	}
+	buf.WriteByte('}')
+
+	return buf.Bytes()
+}
@@ -0,0 +1,22 @@
+package protocol
+
+type Location struct {
+	Vertex
+	Range RangeData `json:"range"`
+}
+
+func NewLocation(id uint64, start, end Pos) Location {
+	return Location{
+		Vertex: Vertex{
+			Element: Element{
+				ID:   id,
+				Type: ElementVertex,
+			},
+			Label: VertexLocation,
+		},
+		Range: RangeData{
+			Start: start,
+			End:   end,
+		},
+	}
+}
@@ -0,0 +1,328 @@
+// Package merge combines several LSIF dumps, such as those produced by sharded indexing
+// workers, into a single dump.
+package merge
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+
+	jsoniter "github.com/json-iterator/go"
+	protocol "github.com/sourcegraph/lsif-protocol"
+	"github.com/sourcegraph/lsif-protocol/internal/object"
+	"github.com/sourcegraph/lsif-protocol/reader"
+	"github.com/sourcegraph/lsif-protocol/writer"
+)
+
+// Merge reads the given dumps in order and writes a single dump to the given writer.
+//
+// The identifiers of each dump are remapped into a shared identifier space so that they
+// do not collide. Only the first metaData vertex is written. Documents with the same URI
+// and package information vertices with the same name, manager, and version are written
+// once, and references to their duplicates are redirected to the first occurrence. Import
+// monikers are linked by a nextMoniker edge to the export moniker with the same scheme and
+// identifier in another dump.
+//
+// The $event vertices of each dump are retained. A document that appears in several dumps
+// is therefore delimited by a balanced pair of begin and end events around the data of each
+// dump in which it appears.
+//
+// The given writer is not flushed.
+func Merge(ctx context.Context, w writer.JSONWriter, readers ...io.Reader) error {
+	m := &merger{
+		w:         w,
+		documents: map[string]uint64{},
+		packages:  map[packageKey]uint64{},
+		exports:   map[monikerKey]monikerInfo{},
+		linked:    map[uint64]struct{}{},
+	}
+
+	for shard, r := range readers {
+		if err := m.mergeShard(ctx, shard, r); err != nil {
+			return fmt.Errorf("dump %d: %s", shard+1, err)
+		}
+	}
+
+	m.linkMonikers()
+	return writer.Err(w)
+}
+
+var api = jsoniter.ConfigFastest
+
+type packageKey struct {
+	name    string
+	manager string
+	version string
+}
+
+type monikerKey struct {
+	scheme     string
+	identifier string
+}
+
+type monikerInfo struct {
+	id    uint64
+	shard int
+}
+
+type merger struct {
+	w          writer.JSONWriter
+	id         uint64
+	metaDataID uint64
+	documents  map[string]uint64
+	packages   map[packageKey]uint64
+	exports    map[monikerKey]monikerInfo
+	imports    []importMoniker
+	linked     map[uint64]struct{}
+}
+
+type importMoniker struct {
+	monikerInfo
+	key monikerKey
+}
+
+// shard holds the state of the dump currently being merged.
+type shard struct {
+	index int
+
+	// ids maps the raw identifiers of the dump to identifiers in the merged dump.
+	ids map[string]uint64
+}
+
+func (m *merger) mergeShard(ctx context.Context, index int, r io.Reader) error {
+	r, err := reader.Decompress(r)
+	if err != nil {
+		return err
+	}
+
+	s := &shard{
+		index: index,
+		ids:   map[string]uint64{},
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, reader.LineBufferSize), reader.LineBufferSize)
+
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		if err := m.mergeElement(s, line); err != nil {
+			return fmt.Errorf("line %d: %s", lineNumber, err)
+		}
+
+		if err := writer.Err(m.w); err != nil {
+			return err
+		}
+	}
+
+	return scanner.Err()
+}
+
+func (m *merger) mergeElement(s *shard, line []byte) error {
+	o, err := object.Parse(line)
+	if err != nil {
+		return err
+	}
+
+	rawID, ok := o.Get("id")
+	if !ok {
+		return fmt.Errorf("element has no id")
+	}
+
+	typ := o.GetString("type")
+	label := o.GetString("label")
+
+	if typ == "vertex" {
+		switch label {
+		case "metaData":
+			if m.metaDataID != 0 {
+				s.deduplicate(rawID, m.metaDataID)
+				return nil
+			}
+
+			m.metaDataID = s.remap(m, rawID)
+
+		case "document":
+			uri := o.GetString("uri")
+			if id, ok := m.documents[uri]; ok {
+				s.deduplicate(rawID, id)
+				return nil
+			}
+
+			m.documents[uri] = s.remap(m, rawID)
+
+		case "packageInformation":
+			key := packageKey{name: o.GetString("name"), manager: o.GetString("manager"), version: o.GetString("version")}
+			if id, ok := m.packages[key]; ok {
+				s.deduplicate(rawID, id)
+				return nil
+			}
+
+			m.packages[key] = s.remap(m, rawID)
+
+		case "moniker":
+			id := s.remap(m, rawID)
+			info := monikerInfo{id: id, shard: s.index}
+			key := monikerKey{scheme: o.GetString("scheme"), identifier: o.GetString("identifier")}
+
+			switch o.GetString("kind") {
+			case "export":
+				if _, ok := m.exports[key]; !ok {
+					m.exports[key] = info
+				}
+			case "import":
+				m.imports = append(m.imports, importMoniker{monikerInfo: info, key: key})
+			}
+
+		case "documentSymbolResult":
+			if raw, ok := o.Get("result"); ok {
+				result, err := s.remapSymbols(m, raw)
+				if err != nil {
+					return err
+				}
+				o.Set("result", result)
+			}
+		}
+	}
+
+	keys := []string{"id", "outV", "inV", "document"}
+	if label == "$event" {
+		// The data of an $event vertex is the identifier of the delimited document or project
+		keys = append(keys, "data")
+	}
+
+	remapped := map[string]uint64{}
+	for _, key := range keys {
+		if raw, ok := o.Get(key); ok {
+			remapped[key] = s.remap(m, raw)
+			o.Set(key, formatID(remapped[key]))
+		}
+	}
+
+	var inVs []json.RawMessage
+	hasInVs, err := o.Unmarshal("inVs", &inVs)
+	if err != nil {
+		return err
+	}
+	if hasInVs {
+		var ids []byte
+		ids = append(ids, '[')
+		for i, inV := range inVs {
+			if i > 0 {
+				ids = append(ids, ',')
+			}
+			ids = append(ids, formatID(s.remap(m, inV))...)
+		}
+		ids = append(ids, ']')
+
+		o.Set("inVs", ids)
+	}
+
+	if typ == "edge" && label == "nextMoniker" {
+		m.linked[remapped["outV"]] = struct{}{}
+	}
+
+	m.w.Write(json.RawMessage(o.Marshal()))
+	return nil
+}
+
+// linkMonikers emits a nextMoniker edge from each import moniker to the export moniker
+// with the same scheme and identifier from another dump.
+func (m *merger) linkMonikers() {
+	for _, moniker := range m.imports {
+		if _, ok := m.linked[moniker.id]; ok {
+			continue
+		}
+
+		export, ok := m.exports[moniker.key]
+		if !ok || export.shard == moniker.shard {
+			continue
+		}
+
+		m.id++
+		m.w.Write(protocol.NewNextMonikerEdge(m.id, moniker.id, export.id))
+		m.linked[moniker.id] = struct{}{}
+	}
+}
+
+// remapSymbols remaps the range identifiers of the given range-based document symbols,
+// including those of nested children. Document symbols with inline data have no
+// identifiers and are returned unchanged.
+func (s *shard) remapSymbols(m *merger, raw []byte) ([]byte, error) {
+	var symbols []json.RawMessage
+	if err := api.Unmarshal(raw, &symbols); err != nil {
+		return nil, err
+	}
+
+	for i, symbol := range symbols {
+		o, err := object.Parse(symbol)
+		if err != nil {
+			return nil, err
+		}
+
+		if id, ok := o.Get("id"); ok {
+			o.Set("id", formatID(s.remap(m, id)))
+		}
+		if children, ok := o.Get("children"); ok {
+			remapped, err := s.remapSymbols(m, children)
+			if err != nil {
+				return nil, err
+			}
+			o.Set("children", remapped)
+		}
+
+		symbols[i] = o.Marshal()
+	}
+
+	return api.Marshal(symbols)
+}
+
+// remap returns the identifier in the merged dump for the given raw identifier, allocating
+// a new identifier on first use.
+func (s *shard) remap(m *merger, raw []byte) uint64 {
+	key := idKey(raw)
+	if id, ok := s.ids[key]; ok {
+		return id
+	}
+
+	m.id++
+	s.ids[key] = m.id
+	return m.id
+}
+
+// deduplicate maps the given raw identifier to the identifier of an equivalent vertex
+// that has already been written.
+func (s *shard) deduplicate(raw []byte, id uint64) {
+	s.ids[idKey(raw)] = id
+}
+
+// idKey returns the key of the given raw identifier in a shard's identifier map. String
+// identifiers are unquoted so that the identifiers 1 and "1" refer to the same element.
+func idKey(raw []byte) string {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 || raw[0] != '"' {
+		return string(raw)
+	}
+
+	var id string
+	if err := api.Unmarshal(raw, &id); err != nil {
+		return string(raw)
+	}
+
+	return id
+}
+
+func formatID(id uint64) []byte {
+	return strconv.AppendUint(nil, id, 10)
+}
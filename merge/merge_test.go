@@ -0,0 +1,185 @@
+package merge
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sourcegraph/lsif-protocol/internal/lsiftest"
+	"github.com/sourcegraph/lsif-protocol/writer"
+)
+
+func TestMerge(t *testing.T) {
+	shard1 := []string{
+		`{"id":1,"type":"vertex","label":"metaData","version":"0.4.3","projectRoot":"file:///build"}`,
+		`{"id":2,"type":"vertex","label":"document","uri":"file:///build/a.go","languageId":"go"}`,
+		`{"id":3,"type":"vertex","label":"range","start":{"line":1,"character":5},"end":{"line":1,"character":8}}`,
+		`{"id":4,"type":"edge","label":"contains","outV":2,"inVs":[3]}`,
+		`{"id":5,"type":"vertex","label":"moniker","kind":"export","scheme":"gomod","identifier":"a:Foo"}`,
+		`{"id":6,"type":"vertex","label":"packageInformation","name":"a","manager":"gomod","version":"v1"}`,
+		`{"id":7,"type":"edge","label":"packageInformation","outV":5,"inV":6}`,
+	}
+
+	shard2 := []string{
+		`{"id":1,"type":"vertex","label":"metaData","version":"0.4.3","projectRoot":"file:///build"}`,
+		`{"id":2,"type":"vertex","label":"document","uri":"file:///build/b.go","languageId":"go"}`,
+		`{"id":3,"type":"vertex","label":"range","start":{"line":3,"character":1},"end":{"line":3,"character":4}}`,
+		`{"id":4,"type":"edge","label":"contains","outV":2,"inVs":[3]}`,
+		`{"id":5,"type":"vertex","label":"moniker","kind":"import","scheme":"gomod","identifier":"a:Foo"}`,
+		`{"id":6,"type":"vertex","label":"packageInformation","name":"a","manager":"gomod","version":"v1"}`,
+		`{"id":7,"type":"edge","label":"packageInformation","outV":5,"inV":6}`,
+	}
+
+	expected := []string{
+		`{"id":1,"type":"vertex","label":"metaData","version":"0.4.3","projectRoot":"file:///build"}`,
+		`{"id":2,"type":"vertex","label":"document","uri":"file:///build/a.go","languageId":"go"}`,
+		`{"id":3,"type":"vertex","label":"range","start":{"line":1,"character":5},"end":{"line":1,"character":8}}`,
+		`{"id":4,"type":"edge","label":"contains","outV":2,"inVs":[3]}`,
+		`{"id":5,"type":"vertex","label":"moniker","kind":"export","scheme":"gomod","identifier":"a:Foo"}`,
+		`{"id":6,"type":"vertex","label":"packageInformation","name":"a","manager":"gomod","version":"v1"}`,
+		`{"id":7,"type":"edge","label":"packageInformation","outV":5,"inV":6}`,
+		`{"id":8,"type":"vertex","label":"document","uri":"file:///build/b.go","languageId":"go"}`,
+		`{"id":9,"type":"vertex","label":"range","start":{"line":3,"character":1},"end":{"line":3,"character":4}}`,
+		`{"id":10,"type":"edge","label":"contains","outV":8,"inVs":[9]}`,
+		`{"id":11,"type":"vertex","label":"moniker","kind":"import","scheme":"gomod","identifier":"a:Foo"}`,
+		`{"id":12,"type":"edge","label":"packageInformation","outV":11,"inV":6}`,
+		`{"id":13,"type":"edge","label":"nextMoniker","outV":11,"inV":5}`,
+	}
+	if diff := cmp.Diff(expected, merge(t, shard1, shard2)); diff != "" {
+		t.Errorf("unexpected output (-want +got):\n%s", diff)
+	}
+}
+
+func TestMergeDuplicateDocuments(t *testing.T) {
+	shard1 := []string{
+		`{"id":1,"type":"vertex","label":"document","uri":"file:///build/a.go","languageId":"go"}`,
+		`{"id":2,"type":"vertex","label":"$event","kind":"begin","scope":"document","data":1}`,
+		`{"id":3,"type":"vertex","label":"range","start":{"line":1,"character":5},"end":{"line":1,"character":8}}`,
+		`{"id":4,"type":"edge","label":"contains","outV":1,"inVs":[3]}`,
+		`{"id":5,"type":"vertex","label":"$event","kind":"end","scope":"document","data":1}`,
+	}
+
+	shard2 := []string{
+		`{"id":1,"type":"vertex","label":"document","uri":"file:///build/a.go","languageId":"go"}`,
+		`{"id":2,"type":"vertex","label":"$event","kind":"begin","scope":"document","data":1}`,
+		`{"id":3,"type":"vertex","label":"range","start":{"line":7,"character":2},"end":{"line":7,"character":4}}`,
+		`{"id":4,"type":"edge","label":"contains","outV":1,"inVs":[3]}`,
+		`{"id":5,"type":"vertex","label":"definitionResult"}`,
+		`{"id":6,"type":"edge","label":"item","outV":5,"inVs":[3],"document":1}`,
+		`{"id":7,"type":"vertex","label":"$event","kind":"end","scope":"document","data":1}`,
+	}
+
+	// The data of the second dump refers to the first occurrence of the document and is
+	// delimited by its own pair of events
+	expected := []string{
+		`{"id":1,"type":"vertex","label":"document","uri":"file:///build/a.go","languageId":"go"}`,
+		`{"id":2,"type":"vertex","label":"$event","kind":"begin","scope":"document","data":1}`,
+		`{"id":3,"type":"vertex","label":"range","start":{"line":1,"character":5},"end":{"line":1,"character":8}}`,
+		`{"id":4,"type":"edge","label":"contains","outV":1,"inVs":[3]}`,
+		`{"id":5,"type":"vertex","label":"$event","kind":"end","scope":"document","data":1}`,
+		`{"id":6,"type":"vertex","label":"$event","kind":"begin","scope":"document","data":1}`,
+		`{"id":7,"type":"vertex","label":"range","start":{"line":7,"character":2},"end":{"line":7,"character":4}}`,
+		`{"id":8,"type":"edge","label":"contains","outV":1,"inVs":[7]}`,
+		`{"id":9,"type":"vertex","label":"definitionResult"}`,
+		`{"id":10,"type":"edge","label":"item","outV":9,"inVs":[7],"document":1}`,
+		`{"id":11,"type":"vertex","label":"$event","kind":"end","scope":"document","data":1}`,
+	}
+	if diff := cmp.Diff(expected, merge(t, shard1, shard2)); diff != "" {
+		t.Errorf("unexpected output (-want +got):\n%s", diff)
+	}
+}
+
+func TestMergeExistingNextMoniker(t *testing.T) {
+	shard1 := []string{
+		`{"id":1,"type":"vertex","label":"moniker","kind":"export","scheme":"gomod","identifier":"a:Foo"}`,
+	}
+
+	shard2 := []string{
+		`{"id":1,"type":"vertex","label":"moniker","kind":"import","scheme":"gomod","identifier":"a:Foo"}`,
+		`{"id":2,"type":"vertex","label":"moniker","kind":"local","scheme":"gomod","identifier":"b:Foo"}`,
+		`{"id":3,"type":"edge","label":"nextMoniker","outV":1,"inV":2}`,
+	}
+
+	expected := []string{
+		`{"id":1,"type":"vertex","label":"moniker","kind":"export","scheme":"gomod","identifier":"a:Foo"}`,
+		`{"id":2,"type":"vertex","label":"moniker","kind":"import","scheme":"gomod","identifier":"a:Foo"}`,
+		`{"id":3,"type":"vertex","label":"moniker","kind":"local","scheme":"gomod","identifier":"b:Foo"}`,
+		`{"id":4,"type":"edge","label":"nextMoniker","outV":2,"inV":3}`,
+	}
+	if diff := cmp.Diff(expected, merge(t, shard1, shard2)); diff != "" {
+		t.Errorf("unexpected output (-want +got):\n%s", diff)
+	}
+}
+
+func TestMergeRangeBasedDocumentSymbols(t *testing.T) {
+	shard1 := []string{
+		`{"id":1,"type":"vertex","label":"document","uri":"file:///build/a.go","languageId":"go"}`,
+	}
+
+	shard2 := []string{
+		`{"id":1,"type":"vertex","label":"document","uri":"file:///build/b.go","languageId":"go"}`,
+		`{"id":2,"type":"vertex","label":"range","start":{"line":1,"character":5},"end":{"line":1,"character":8}}`,
+		`{"id":3,"type":"vertex","label":"range","start":{"line":2,"character":5},"end":{"line":2,"character":8}}`,
+		`{"id":4,"type":"vertex","label":"documentSymbolResult","result":[{"id":2,"children":[{"id":3}]}]}`,
+	}
+
+	expected := []string{
+		`{"id":1,"type":"vertex","label":"document","uri":"file:///build/a.go","languageId":"go"}`,
+		`{"id":2,"type":"vertex","label":"document","uri":"file:///build/b.go","languageId":"go"}`,
+		`{"id":3,"type":"vertex","label":"range","start":{"line":1,"character":5},"end":{"line":1,"character":8}}`,
+		`{"id":4,"type":"vertex","label":"range","start":{"line":2,"character":5},"end":{"line":2,"character":8}}`,
+		`{"id":5,"type":"vertex","label":"documentSymbolResult","result":[{"id":3,"children":[{"id":4}]}]}`,
+	}
+	if diff := cmp.Diff(expected, merge(t, shard1, shard2)); diff != "" {
+		t.Errorf("unexpected output (-want +got):\n%s", diff)
+	}
+}
+
+func TestMergeNonEventData(t *testing.T) {
+	shard1 := []string{
+		`{"id":1,"type":"vertex","label":"document","uri":"file:///build/a.go","languageId":"go"}`,
+	}
+
+	shard2 := []string{
+		`{"id":1,"type":"vertex","label":"hoverResult","result":{"contents":[]},"data":1}`,
+	}
+
+	expected := []string{
+		`{"id":1,"type":"vertex","label":"document","uri":"file:///build/a.go","languageId":"go"}`,
+		`{"id":2,"type":"vertex","label":"hoverResult","result":{"contents":[]},"data":1}`,
+	}
+	if diff := cmp.Diff(expected, merge(t, shard1, shard2)); diff != "" {
+		t.Errorf("unexpected output (-want +got):\n%s", diff)
+	}
+}
+
+func TestMergeQuotedIdentifiers(t *testing.T) {
+	shard1 := []string{
+		`{"id":"1","type":"vertex","label":"document","uri":"file:///build/a.go","languageId":"go"}`,
+		`{"id":"2","type":"vertex","label":"range","start":{"line":1,"character":5},"end":{"line":1,"character":8}}`,
+		`{"id":"3","type":"edge","label":"contains","outV":1,"inVs":[2]}`,
+	}
+
+	expected := []string{
+		`{"id":1,"type":"vertex","label":"document","uri":"file:///build/a.go","languageId":"go"}`,
+		`{"id":2,"type":"vertex","label":"range","start":{"line":1,"character":5},"end":{"line":1,"character":8}}`,
+		`{"id":3,"type":"edge","label":"contains","outV":1,"inVs":[2]}`,
+	}
+	if diff := cmp.Diff(expected, merge(t, shard1)); diff != "" {
+		t.Errorf("unexpected output (-want +got):\n%s", diff)
+	}
+}
+
+// merge merges the given dumps and returns the lines of the output.
+func merge(t *testing.T, shards ...[]string) []string {
+	var readers []io.Reader
+	for _, shard := range shards {
+		readers = append(readers, strings.NewReader(strings.Join(shard, "\n")))
+	}
+
+	return lsiftest.Lines(t, func(w writer.JSONWriter) error {
+		return Merge(context.Background(), w, readers...)
+	})
+}
@@ -0,0 +1,177 @@
+// Package query answers position-based navigation queries against an LSIF dump.
+package query
+
+import (
+	"context"
+	"io"
+	"sort"
+	"strings"
+
+	protocol "github.com/sourcegraph/lsif-protocol"
+	"github.com/sourcegraph/lsif-protocol/reader"
+)
+
+// Index answers queries of the form "what is at this position?" against a correlated dump.
+type Index struct {
+	graph *reader.Graph
+}
+
+// New creates an index over the given correlated graph.
+func New(graph *reader.Graph) *Index {
+	return &Index{graph: graph}
+}
+
+// Load reads and correlates the given LSIF dump and returns an index over it.
+func Load(ctx context.Context, r io.Reader) (*Index, error) {
+	graph, err := reader.Correlate(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	return New(graph), nil
+}
+
+// Graph returns the correlated graph underlying the index.
+func (i *Index) Graph() *reader.Graph {
+	return i.graph
+}
+
+// Result describes the data attached to a range enclosing a queried position.
+type Result struct {
+	RangeID         int
+	Range           reader.Range
+	Hover           string
+	Definitions     []reader.Location
+	References      []reader.Location
+	Implementations []reader.Location
+	Monikers        []reader.Moniker
+}
+
+// At returns the data attached to each range of the given document that encloses the
+// given position. Results are ordered from the innermost to the outermost range.
+func (i *Index) At(uri string, pos protocol.Pos) []Result {
+	var results []Result
+	for _, id := range i.Ranges(uri, pos) {
+		r, _ := i.graph.Range(id)
+		hover, _ := i.graph.Hover(id)
+
+		results = append(results, Result{
+			RangeID:         id,
+			Range:           r,
+			Hover:           hover,
+			Definitions:     i.graph.Definitions(id),
+			References:      i.graph.References(id),
+			Implementations: i.graph.Implementations(id),
+			Monikers:        i.graph.Monikers(id),
+		})
+	}
+
+	return results
+}
+
+// Hover returns the hover text of the innermost range enclosing the given position that
+// has hover text.
+func (i *Index) Hover(uri string, pos protocol.Pos) (string, bool) {
+	for _, id := range i.Ranges(uri, pos) {
+		if hover, ok := i.graph.Hover(id); ok {
+			return hover, true
+		}
+	}
+
+	return "", false
+}
+
+// Definitions returns the definitions of the innermost range enclosing the given position
+// that has definitions.
+func (i *Index) Definitions(uri string, pos protocol.Pos) []reader.Location {
+	return i.firstLocations(uri, pos, i.graph.Definitions)
+}
+
+// References returns the references of the innermost range enclosing the given position
+// that has references.
+func (i *Index) References(uri string, pos protocol.Pos) []reader.Location {
+	return i.firstLocations(uri, pos, i.graph.References)
+}
+
+// Implementations returns the implementations of the innermost range enclosing the given
+// position that has implementations.
+func (i *Index) Implementations(uri string, pos protocol.Pos) []reader.Location {
+	return i.firstLocations(uri, pos, i.graph.Implementations)
+}
+
+func (i *Index) firstLocations(uri string, pos protocol.Pos, f func(id int) []reader.Location) []reader.Location {
+	for _, id := range i.Ranges(uri, pos) {
+		if locations := f(id); len(locations) > 0 {
+			return locations
+		}
+	}
+
+	return nil
+}
+
+// Ranges returns the identifiers of the ranges of the given document that enclose the given
+// position, ordered from the innermost to the outermost range. The end of a range is treated
+// as inclusive so that a position directly after an identifier still resolves to it.
+func (i *Index) Ranges(uri string, pos protocol.Pos) []int {
+	documentID, ok := i.Document(uri)
+	if !ok {
+		return nil
+	}
+	uri, _ = i.graph.DocumentURI(documentID)
+
+	var ids []int
+	for _, documentID := range i.graph.DocumentsByURI(uri) {
+		for _, id := range i.graph.DocumentRanges(documentID) {
+			if r, ok := i.graph.Range(id); ok && contains(r, pos) {
+				ids = append(ids, id)
+			}
+		}
+	}
+
+	sort.SliceStable(ids, func(a, b int) bool {
+		ra, _ := i.graph.Range(ids[a])
+		rb, _ := i.graph.Range(ids[b])
+
+		// Enclosing ranges cannot start after the ranges they enclose, so the range
+		// with the later start (or the earlier end, on a tie) is the inner one.
+		if c := reader.ComparePositions(ra.StartLine, ra.StartCharacter, rb.StartLine, rb.StartCharacter); c != 0 {
+			return c > 0
+		}
+
+		return reader.ComparePositions(ra.EndLine, ra.EndCharacter, rb.EndLine, rb.EndCharacter) < 0
+	})
+
+	return ids
+}
+
+// Document returns the identifier of the document with the given URI. URIs that are not
+// found verbatim are also resolved relative to the project root.
+func (i *Index) Document(uri string) (int, bool) {
+	if id, ok := i.graph.DocumentByURI(uri); ok {
+		return id, true
+	}
+
+	root := i.graph.MetaData.ProjectRoot
+	if root == "" {
+		return 0, false
+	}
+
+	path, ok := reader.RelativePath(root, strings.TrimPrefix(uri, "/"))
+	if !ok {
+		return 0, false
+	}
+
+	for _, id := range i.graph.Documents() {
+		documentURI, _ := i.graph.DocumentURI(id)
+		if documentPath, ok := reader.RelativePath(root, documentURI); ok && documentPath == path {
+			return id, true
+		}
+	}
+
+	return 0, false
+}
+
+func contains(r reader.Range, pos protocol.Pos) bool {
+	return reader.ComparePositions(r.StartLine, r.StartCharacter, pos.Line, pos.Character) <= 0 &&
+		reader.ComparePositions(pos.Line, pos.Character, r.EndLine, r.EndCharacter) <= 0
+}
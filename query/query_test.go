@@ -0,0 +1,100 @@
+package query
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	protocol "github.com/sourcegraph/lsif-protocol"
+	"github.com/sourcegraph/lsif-protocol/reader"
+)
+
+var testDump = strings.Join([]string{
+	`{"id": 1, "type": "vertex", "label": "metaData", "version": "0.4.3", "projectRoot": "file:///test"}`,
+	`{"id": 2, "type": "vertex", "label": "document", "uri": "file:///test/foo.go"}`,
+	`{"id": 3, "type": "vertex", "label": "range", "start": {"line": 1, "character": 0}, "end": {"line": 3, "character": 1}}`,
+	`{"id": 4, "type": "vertex", "label": "range", "start": {"line": 1, "character": 5}, "end": {"line": 1, "character": 8}}`,
+	`{"id": 5, "type": "vertex", "label": "range", "start": {"line": 2, "character": 1}, "end": {"line": 2, "character": 4}}`,
+	`{"id": 6, "type": "edge", "label": "contains", "outV": 2, "inVs": [3, 4, 5]}`,
+	`{"id": 7, "type": "vertex", "label": "resultSet"}`,
+	`{"id": 8, "type": "edge", "label": "next", "outV": 4, "inV": 7}`,
+	`{"id": 9, "type": "edge", "label": "next", "outV": 5, "inV": 7}`,
+	`{"id": 10, "type": "vertex", "label": "hoverResult", "result": {"contents": "func Foo()"}}`,
+	`{"id": 11, "type": "edge", "label": "textDocument/hover", "outV": 7, "inV": 10}`,
+	`{"id": 12, "type": "vertex", "label": "hoverResult", "result": {"contents": "block"}}`,
+	`{"id": 13, "type": "edge", "label": "textDocument/hover", "outV": 3, "inV": 12}`,
+	`{"id": 14, "type": "vertex", "label": "definitionResult"}`,
+	`{"id": 15, "type": "edge", "label": "textDocument/definition", "outV": 7, "inV": 14}`,
+	`{"id": 16, "type": "edge", "label": "item", "outV": 14, "inVs": [4], "document": 2}`,
+	`{"id": 17, "type": "vertex", "label": "referenceResult"}`,
+	`{"id": 18, "type": "edge", "label": "textDocument/references", "outV": 7, "inV": 17}`,
+	`{"id": 19, "type": "edge", "label": "item", "outV": 17, "inVs": [4, 5], "document": 2}`,
+}, "\n")
+
+func TestAt(t *testing.T) {
+	index, err := Load(context.Background(), strings.NewReader(testDump))
+	if err != nil {
+		t.Fatalf("unexpected error loading dump: %s", err)
+	}
+
+	results := index.At("file:///test/foo.go", protocol.Pos{Line: 2, Character: 2})
+
+	var rangeIDs []int
+	var hovers []string
+	for _, result := range results {
+		rangeIDs = append(rangeIDs, result.RangeID)
+		hovers = append(hovers, result.Hover)
+	}
+	if diff := cmp.Diff([]int{5, 3}, rangeIDs); diff != "" {
+		t.Errorf("unexpected ranges (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"func Foo()", "block"}, hovers); diff != "" {
+		t.Errorf("unexpected hovers (-want +got):\n%s", diff)
+	}
+
+	expectedDefinitions := []reader.Location{
+		{
+			DocumentID: 2,
+			URI:        "file:///test/foo.go",
+			RangeID:    4,
+			Range:      reader.Range{StartLine: 1, StartCharacter: 5, EndLine: 1, EndCharacter: 8},
+		},
+	}
+	if diff := cmp.Diff(expectedDefinitions, results[0].Definitions); diff != "" {
+		t.Errorf("unexpected definitions (-want +got):\n%s", diff)
+	}
+	if len(results[0].References) != 2 {
+		t.Errorf("unexpected number of references. want=%d have=%d", 2, len(results[0].References))
+	}
+}
+
+func TestQueries(t *testing.T) {
+	index, err := Load(context.Background(), strings.NewReader(testDump))
+	if err != nil {
+		t.Fatalf("unexpected error loading dump: %s", err)
+	}
+
+	// Resolved relative to the project root; the position is the end of the identifier
+	pos := protocol.Pos{Line: 1, Character: 8}
+
+	if hover, ok := index.Hover("foo.go", pos); !ok || hover != "func Foo()" {
+		t.Errorf("unexpected hover text: %q", hover)
+	}
+
+	if definitions := index.Definitions("foo.go", pos); len(definitions) != 1 || definitions[0].RangeID != 4 {
+		t.Errorf("unexpected definitions: %v", definitions)
+	}
+
+	if references := index.References("foo.go", pos); len(references) != 2 {
+		t.Errorf("unexpected references: %v", references)
+	}
+
+	if hover, ok := index.Hover("file:///test/foo.go", protocol.Pos{Line: 3, Character: 0}); !ok || hover != "block" {
+		t.Errorf("unexpected hover text: %q", hover)
+	}
+
+	if ranges := index.Ranges("file:///test/foo.go", protocol.Pos{Line: 5, Character: 0}); len(ranges) != 0 {
+		t.Errorf("unexpected ranges: %v", ranges)
+	}
+}
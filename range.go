@@ -2,8 +2,9 @@ package protocol
 
 type Range struct {
 	Vertex
-	Start Pos `json:"start"`
-	End   Pos `json:"end"`
+	Start Pos       `json:"start"`
+	End   Pos       `json:"end"`
+	Tag   *RangeTag `json:"tag,omitempty"`
 }
 
 type Pos struct {
@@ -24,3 +25,38 @@ func NewRange(id uint64, start, end Pos) Range {
 		End:   end,
 	}
 }
+
+func NewRangeWithTag(id uint64, start, end Pos, tag RangeTag) Range {
+	r := NewRange(id, start, end)
+	r.Tag = &tag
+	return r
+}
+
+// RangeTag describes the symbol at a range so that consumers can build an
+// outline of a document without a separate documentSymbol request. The kind,
+// full range, and detail are only meaningful for declaration and definition
+// tags.
+type RangeTag struct {
+	Type       RangeTagType `json:"type"`
+	Text       string       `json:"text"`
+	Kind       SymbolKind   `json:"kind,omitempty"`
+	Deprecated bool         `json:"deprecated,omitempty"`
+	FullRange  *RangeData   `json:"fullRange,omitempty"`
+	Detail     string       `json:"detail,omitempty"`
+}
+
+type RangeTagType string
+
+const (
+	RangeTagDeclaration RangeTagType = "declaration"
+	RangeTagDefinition  RangeTagType = "definition"
+	RangeTagReference   RangeTagType = "reference"
+	RangeTagUnknown     RangeTagType = "unknown"
+)
+
+// RangeData is an LSP range. It is embedded in the result payloads of
+// vertices such as diagnosticResult and documentSymbolResult.
+type RangeData struct {
+	Start Pos `json:"start"`
+	End   Pos `json:"end"`
+}
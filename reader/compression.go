@@ -0,0 +1,28 @@
+package reader
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"io"
+)
+
+// gzipMagic is the header that begins every gzip stream.
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// Decompress returns a reader that yields the decompressed content of the given reader
+// if it begins with a gzip header, and the unmodified content otherwise.
+func Decompress(r io.Reader) (io.Reader, error) {
+	br := bufio.NewReader(r)
+
+	header, err := br.Peek(len(gzipMagic))
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	if bytes.Equal(header, gzipMagic) {
+		return gzip.NewReader(br)
+	}
+
+	return br, nil
+}
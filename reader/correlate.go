@@ -0,0 +1,437 @@
+package reader
+
+import (
+	"context"
+	"io"
+	"sort"
+)
+
+// Graph is an in-memory index of an LSIF dump that resolves ranges to the results
+// attached to them either directly or through chains of next edges to result sets.
+type Graph struct {
+	MetaData MetaData
+
+	labels         map[int]string
+	payloads       map[int]interface{}
+	documentsByURI map[string][]int
+	contains       map[int][]int
+	rangeDocuments map[int]int
+	next           map[int]int
+	edges          map[string]map[int]int
+	monikers       map[int][]int
+	nextMonikers   map[int]int
+	packages       map[int]int
+	items          map[int][]item
+}
+
+// item is the payload of an item edge attaching vertices of a document to a result.
+type item struct {
+	document int
+	inVs     []int
+}
+
+// Location is a range within a particular document.
+type Location struct {
+	DocumentID int
+	URI        string
+	RangeID    int
+	Range      Range
+}
+
+// Correlate reads the given LSIF dump and builds an in-memory graph from its elements.
+// The first error encountered while reading the input is returned.
+func Correlate(ctx context.Context, r io.Reader) (*Graph, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	g := newGraph()
+	for pair := range Read(ctx, r) {
+		if pair.Err != nil {
+			return nil, pair.Err
+		}
+
+		g.add(pair.Element)
+	}
+
+	return g, nil
+}
+
+func newGraph() *Graph {
+	return &Graph{
+		labels:         map[int]string{},
+		payloads:       map[int]interface{}{},
+		documentsByURI: map[string][]int{},
+		contains:       map[int][]int{},
+		rangeDocuments: map[int]int{},
+		next:           map[int]int{},
+		edges:          map[string]map[int]int{},
+		monikers:       map[int][]int{},
+		nextMonikers:   map[int]int{},
+		packages:       map[int]int{},
+		items:          map[int][]item{},
+	}
+}
+
+func (g *Graph) add(element Element) {
+	if element.Type == "vertex" {
+		g.labels[element.ID] = element.Label
+		if element.Payload != nil {
+			g.payloads[element.ID] = element.Payload
+		}
+
+		switch payload := element.Payload.(type) {
+		case MetaData:
+			g.MetaData = payload
+		case Document:
+			g.documentsByURI[payload.URI] = append(g.documentsByURI[payload.URI], element.ID)
+		}
+
+		return
+	}
+
+	edge, ok := element.Payload.(Edge)
+	if !ok {
+		return
+	}
+
+	switch element.Label {
+	case "contains":
+		g.contains[edge.OutV] = append(g.contains[edge.OutV], edge.InVs...)
+		if g.labels[edge.OutV] == "document" {
+			for _, inV := range edge.InVs {
+				g.rangeDocuments[inV] = edge.OutV
+			}
+		}
+
+	case "item":
+		g.items[edge.OutV] = append(g.items[edge.OutV], item{document: edge.Document, inVs: edge.InVs})
+
+	case "next":
+		g.next[edge.OutV] = edge.InV
+
+	case "moniker":
+		g.monikers[edge.OutV] = append(g.monikers[edge.OutV], edge.InV)
+
+	case "nextMoniker":
+		g.nextMonikers[edge.OutV] = edge.InV
+
+	case "packageInformation":
+		g.packages[edge.OutV] = edge.InV
+
+	default:
+		if _, ok := g.edges[element.Label]; !ok {
+			g.edges[element.Label] = map[int]int{}
+		}
+		g.edges[element.Label][edge.OutV] = edge.InV
+	}
+}
+
+// Label returns the label of the vertex with the given identifier.
+func (g *Graph) Label(id int) (string, bool) {
+	label, ok := g.labels[id]
+	return label, ok
+}
+
+// Payload returns the decoded payload of the vertex with the given identifier.
+func (g *Graph) Payload(id int) (interface{}, bool) {
+	payload, ok := g.payloads[id]
+	return payload, ok
+}
+
+// Documents returns the identifiers of all document vertices ordered by URI. Documents
+// that share a URI are ordered as they appear in the dump.
+func (g *Graph) Documents() []int {
+	uris := make([]string, 0, len(g.documentsByURI))
+	for uri := range g.documentsByURI {
+		uris = append(uris, uri)
+	}
+	sort.Strings(uris)
+
+	ids := make([]int, 0, len(uris))
+	for _, uri := range uris {
+		ids = append(ids, g.documentsByURI[uri]...)
+	}
+
+	return ids
+}
+
+// DocumentURI returns the URI of the document with the given identifier.
+func (g *Graph) DocumentURI(id int) (string, bool) {
+	if g.labels[id] != "document" {
+		return "", false
+	}
+
+	document, ok := g.payloads[id].(Document)
+	return document.URI, ok
+}
+
+// DocumentByURI returns the identifier of the document with the given URI. If several
+// documents share the URI, the first one in the dump is returned.
+func (g *Graph) DocumentByURI(uri string) (int, bool) {
+	ids := g.documentsByURI[uri]
+	if len(ids) == 0 {
+		return 0, false
+	}
+
+	return ids[0], true
+}
+
+// DocumentsByURI returns the identifiers of all documents with the given URI in the order
+// they appear in the dump.
+func (g *Graph) DocumentsByURI(uri string) []int {
+	return g.documentsByURI[uri]
+}
+
+// DocumentRanges returns the identifiers of the ranges contained by the given document
+// ordered by their position in the document.
+func (g *Graph) DocumentRanges(documentID int) []int {
+	var ids []int
+	for _, id := range g.contains[documentID] {
+		if g.labels[id] == "range" {
+			ids = append(ids, id)
+		}
+	}
+
+	sort.Slice(ids, func(i, j int) bool {
+		ri, _ := g.Range(ids[i])
+		rj, _ := g.Range(ids[j])
+		return compareRanges(ri, rj) < 0
+	})
+
+	return ids
+}
+
+// Range returns the range with the given identifier.
+func (g *Graph) Range(id int) (Range, bool) {
+	r, ok := g.payloads[id].(Range)
+	return r, ok
+}
+
+// RangeDocument returns the identifier of the document containing the given range.
+func (g *Graph) RangeDocument(rangeID int) (int, bool) {
+	id, ok := g.rangeDocuments[rangeID]
+	return id, ok
+}
+
+// Hover returns the hover text attached to the given range or result set.
+func (g *Graph) Hover(id int) (string, bool) {
+	resultID, ok := g.result(id, "textDocument/hover")
+	if !ok {
+		return "", false
+	}
+
+	text, ok := g.payloads[resultID].(string)
+	return text, ok
+}
+
+// Definitions returns the locations of the definitions of the given range or result set.
+func (g *Graph) Definitions(id int) []Location {
+	return g.locations(id, "textDocument/definition")
+}
+
+// Declarations returns the locations of the declarations of the given range or result set.
+func (g *Graph) Declarations(id int) []Location {
+	return g.locations(id, "textDocument/declaration")
+}
+
+// TypeDefinitions returns the locations of the type definitions of the given range or result set.
+func (g *Graph) TypeDefinitions(id int) []Location {
+	return g.locations(id, "textDocument/typeDefinition")
+}
+
+// References returns the locations of the references to the given range or result set.
+func (g *Graph) References(id int) []Location {
+	return g.locations(id, "textDocument/references")
+}
+
+// Implementations returns the locations of the implementations of the given range or result set.
+func (g *Graph) Implementations(id int) []Location {
+	return g.locations(id, "textDocument/implementation")
+}
+
+// Monikers returns the monikers attached to the given range or to any result set reachable
+// from it via next edges. Monikers linked by nextMoniker edges are included.
+func (g *Graph) Monikers(id int) []Moniker {
+	var monikers []Moniker
+	for _, monikerID := range g.MonikerIDs(id) {
+		if moniker, ok := g.payloads[monikerID].(Moniker); ok {
+			monikers = append(monikers, moniker)
+		}
+	}
+
+	return monikers
+}
+
+// MonikerIDs returns the identifiers of the monikers that Monikers would return.
+func (g *Graph) MonikerIDs(id int) []int {
+	var ids []int
+	visited := map[int]struct{}{}
+
+	for _, current := range g.nextChain(id) {
+		for _, monikerID := range g.monikers[current] {
+			for ; monikerID != 0; monikerID = g.nextMonikers[monikerID] {
+				if _, ok := visited[monikerID]; ok {
+					break
+				}
+				visited[monikerID] = struct{}{}
+				ids = append(ids, monikerID)
+			}
+		}
+	}
+
+	return ids
+}
+
+// PackageInformation returns the package information attached to the given moniker.
+func (g *Graph) PackageInformation(monikerID int) (PackageInformation, bool) {
+	packageInformation, ok := g.payloads[g.packages[monikerID]].(PackageInformation)
+	return packageInformation, ok
+}
+
+// DocumentSymbols returns the payload of the documentSymbolResult attached to the given
+// document, which is either a slice of DocumentSymbol or RangeBasedDocumentSymbol values.
+func (g *Graph) DocumentSymbols(documentID int) (interface{}, bool) {
+	resultID, ok := g.edges["textDocument/documentSymbol"][documentID]
+	if !ok {
+		return nil, false
+	}
+
+	payload, ok := g.payloads[resultID]
+	return payload, ok
+}
+
+// FoldingRanges returns the folding ranges attached to the given document.
+func (g *Graph) FoldingRanges(documentID int) []FoldingRange {
+	foldingRanges, _ := g.payloads[g.edges["textDocument/foldingRange"][documentID]].([]FoldingRange)
+	return foldingRanges
+}
+
+// DocumentLinks returns the document links attached to the given document.
+func (g *Graph) DocumentLinks(documentID int) []DocumentLink {
+	documentLinks, _ := g.payloads[g.edges["textDocument/documentLink"][documentID]].([]DocumentLink)
+	return documentLinks
+}
+
+// Diagnostics returns the diagnostics attached to the given document.
+func (g *Graph) Diagnostics(documentID int) []Diagnostic {
+	diagnostics, _ := g.payloads[g.edges["textDocument/diagnostic"][documentID]].([]Diagnostic)
+	return diagnostics
+}
+
+// nextChain returns the given identifier followed by the identifiers of the result
+// sets reachable from it via next edges.
+func (g *Graph) nextChain(id int) []int {
+	chain := []int{id}
+	visited := map[int]struct{}{id: {}}
+
+	for {
+		next, ok := g.next[id]
+		if !ok {
+			break
+		}
+		if _, ok := visited[next]; ok {
+			break
+		}
+
+		visited[next] = struct{}{}
+		chain = append(chain, next)
+		id = next
+	}
+
+	return chain
+}
+
+// result returns the result vertex attached via an edge with the given label to the
+// given identifier or to the first result set reachable from it that has such an edge.
+func (g *Graph) result(id int, label string) (int, bool) {
+	for _, current := range g.nextChain(id) {
+		if resultID, ok := g.edges[label][current]; ok {
+			return resultID, true
+		}
+	}
+
+	return 0, false
+}
+
+// locations returns the ranges attached to the result reachable from the given identifier
+// via an edge with the given label.
+func (g *Graph) locations(id int, label string) []Location {
+	resultID, ok := g.result(id, label)
+	if !ok {
+		return nil
+	}
+
+	var locations []Location
+	visited := map[int]struct{}{}
+	g.collectLocations(resultID, visited, &locations)
+
+	sort.SliceStable(locations, func(i, j int) bool {
+		if locations[i].URI != locations[j].URI {
+			return locations[i].URI < locations[j].URI
+		}
+
+		return compareRanges(locations[i].Range, locations[j].Range) < 0
+	})
+
+	return locations
+}
+
+// collectLocations appends the ranges attached to the given result via item edges to the
+// given slice. Items that refer to other results (such as referenceResults that link the
+// references of several result sets together) are followed recursively.
+func (g *Graph) collectLocations(resultID int, visited map[int]struct{}, locations *[]Location) {
+	if _, ok := visited[resultID]; ok {
+		return
+	}
+	visited[resultID] = struct{}{}
+
+	for _, item := range g.items[resultID] {
+		for _, inV := range item.inVs {
+			r, ok := g.payloads[inV].(Range)
+			if !ok {
+				if label := g.labels[inV]; label != "range" && label != "" {
+					g.collectLocations(inV, visited, locations)
+				}
+
+				continue
+			}
+
+			if _, ok := visited[inV]; ok {
+				continue
+			}
+			visited[inV] = struct{}{}
+
+			documentID := item.document
+			if documentID == 0 {
+				documentID = g.rangeDocuments[inV]
+			}
+			uri, _ := g.DocumentURI(documentID)
+
+			*locations = append(*locations, Location{
+				DocumentID: documentID,
+				URI:        uri,
+				RangeID:    inV,
+				Range:      r,
+			})
+		}
+	}
+}
+
+// compareRanges orders ranges by their start position, then by their end position.
+func compareRanges(a, b Range) int {
+	if c := ComparePositions(a.StartLine, a.StartCharacter, b.StartLine, b.StartCharacter); c != 0 {
+		return c
+	}
+
+	return ComparePositions(a.EndLine, a.EndCharacter, b.EndLine, b.EndCharacter)
+}
+
+// ComparePositions returns a negative number if the first position precedes the second,
+// a positive number if it follows the second, and zero if the positions are equal.
+func ComparePositions(line1, character1, line2, character2 int) int {
+	if line1 != line2 {
+		return line1 - line2
+	}
+
+	return character1 - character2
+}
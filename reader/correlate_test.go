@@ -0,0 +1,164 @@
+package reader
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+var testDump = strings.Join([]string{
+	`{"id": 1, "type": "vertex", "label": "metaData", "version": "0.4.3", "projectRoot": "file:///test"}`,
+	`{"id": 2, "type": "vertex", "label": "document", "uri": "file:///test/foo.go"}`,
+	`{"id": 3, "type": "vertex", "label": "document", "uri": "file:///test/bar.go"}`,
+	`{"id": 4, "type": "vertex", "label": "range", "start": {"line": 1, "character": 5}, "end": {"line": 1, "character": 8}}`,
+	`{"id": 5, "type": "vertex", "label": "range", "start": {"line": 4, "character": 2}, "end": {"line": 4, "character": 5}}`,
+	`{"id": 6, "type": "vertex", "label": "range", "start": {"line": 7, "character": 1}, "end": {"line": 7, "character": 4}}`,
+	`{"id": 7, "type": "edge", "label": "contains", "outV": 2, "inVs": [5, 4]}`,
+	`{"id": 8, "type": "edge", "label": "contains", "outV": 3, "inVs": [6]}`,
+	`{"id": 9, "type": "vertex", "label": "resultSet"}`,
+	`{"id": 10, "type": "edge", "label": "next", "outV": 4, "inV": 9}`,
+	`{"id": 11, "type": "edge", "label": "next", "outV": 5, "inV": 9}`,
+	`{"id": 12, "type": "edge", "label": "next", "outV": 6, "inV": 9}`,
+	`{"id": 13, "type": "vertex", "label": "hoverResult", "result": {"contents": [{"language": "go", "value": "func Foo()"}]}}`,
+	`{"id": 14, "type": "edge", "label": "textDocument/hover", "outV": 9, "inV": 13}`,
+	`{"id": 15, "type": "vertex", "label": "definitionResult"}`,
+	`{"id": 16, "type": "edge", "label": "textDocument/definition", "outV": 9, "inV": 15}`,
+	`{"id": 17, "type": "edge", "label": "item", "outV": 15, "inVs": [4], "document": 2}`,
+	`{"id": 18, "type": "vertex", "label": "referenceResult"}`,
+	`{"id": 19, "type": "edge", "label": "textDocument/references", "outV": 9, "inV": 18}`,
+	`{"id": 20, "type": "edge", "label": "item", "outV": 18, "inVs": [4], "document": 2, "property": "definitions"}`,
+	`{"id": 21, "type": "edge", "label": "item", "outV": 18, "inVs": [5], "document": 2, "property": "references"}`,
+	`{"id": 22, "type": "edge", "label": "item", "outV": 18, "inVs": [6], "document": 3, "property": "references"}`,
+	`{"id": 23, "type": "vertex", "label": "moniker", "kind": "export", "scheme": "gomod", "identifier": "test:Foo"}`,
+	`{"id": 24, "type": "edge", "label": "moniker", "outV": 9, "inV": 23}`,
+	`{"id": 25, "type": "vertex", "label": "packageInformation", "name": "test", "version": "v1.0.0"}`,
+	`{"id": 26, "type": "edge", "label": "packageInformation", "outV": 23, "inV": 25}`,
+}, "\n")
+
+func TestCorrelate(t *testing.T) {
+	g, err := Correlate(context.Background(), strings.NewReader(testDump))
+	if err != nil {
+		t.Fatalf("unexpected error correlating dump: %s", err)
+	}
+
+	if diff := cmp.Diff(MetaData{Version: "0.4.3", ProjectRoot: "file:///test"}, g.MetaData); diff != "" {
+		t.Errorf("unexpected metadata (-want +got):\n%s", diff)
+	}
+
+	if diff := cmp.Diff([]int{3, 2}, g.Documents()); diff != "" {
+		t.Errorf("unexpected documents (-want +got):\n%s", diff)
+	}
+
+	documentID, ok := g.DocumentByURI("file:///test/foo.go")
+	if !ok || documentID != 2 {
+		t.Fatalf("unexpected document. want=%d have=%d", 2, documentID)
+	}
+
+	if diff := cmp.Diff([]int{4, 5}, g.DocumentRanges(documentID)); diff != "" {
+		t.Errorf("unexpected document ranges (-want +got):\n%s", diff)
+	}
+
+	if hover, ok := g.Hover(5); !ok || hover != "```go\nfunc Foo()\n```" {
+		t.Errorf("unexpected hover text: %q", hover)
+	}
+
+	definition := Location{
+		DocumentID: 2,
+		URI:        "file:///test/foo.go",
+		RangeID:    4,
+		Range:      Range{StartLine: 1, StartCharacter: 5, EndLine: 1, EndCharacter: 8},
+	}
+	if diff := cmp.Diff([]Location{definition}, g.Definitions(6)); diff != "" {
+		t.Errorf("unexpected definitions (-want +got):\n%s", diff)
+	}
+
+	expectedReferences := []Location{
+		{
+			DocumentID: 3,
+			URI:        "file:///test/bar.go",
+			RangeID:    6,
+			Range:      Range{StartLine: 7, StartCharacter: 1, EndLine: 7, EndCharacter: 4},
+		},
+		definition,
+		{
+			DocumentID: 2,
+			URI:        "file:///test/foo.go",
+			RangeID:    5,
+			Range:      Range{StartLine: 4, StartCharacter: 2, EndLine: 4, EndCharacter: 5},
+		},
+	}
+	if diff := cmp.Diff(expectedReferences, g.References(4)); diff != "" {
+		t.Errorf("unexpected references (-want +got):\n%s", diff)
+	}
+
+	if locations := g.Implementations(4); len(locations) != 0 {
+		t.Errorf("unexpected implementations: %v", locations)
+	}
+
+	expectedMonikers := []Moniker{{Kind: "export", Scheme: "gomod", Identifier: "test:Foo"}}
+	if diff := cmp.Diff(expectedMonikers, g.Monikers(4)); diff != "" {
+		t.Errorf("unexpected monikers (-want +got):\n%s", diff)
+	}
+
+	expectedPackageInformation := PackageInformation{Name: "test", Version: "v1.0.0"}
+	if packageInformation, ok := g.PackageInformation(23); !ok || packageInformation != expectedPackageInformation {
+		t.Errorf("unexpected package information: %v", packageInformation)
+	}
+}
+
+func TestCorrelateDuplicateDocuments(t *testing.T) {
+	input := strings.Join([]string{
+		`{"id": 1, "type": "vertex", "label": "document", "uri": "file:///test/foo.go"}`,
+		`{"id": 2, "type": "vertex", "label": "range", "start": {"line": 1, "character": 5}, "end": {"line": 1, "character": 8}}`,
+		`{"id": 3, "type": "edge", "label": "contains", "outV": 1, "inVs": [2]}`,
+		`{"id": 4, "type": "vertex", "label": "document", "uri": "file:///test/foo.go"}`,
+		`{"id": 5, "type": "vertex", "label": "range", "start": {"line": 2, "character": 5}, "end": {"line": 2, "character": 8}}`,
+		`{"id": 6, "type": "edge", "label": "contains", "outV": 4, "inVs": [5]}`,
+	}, "\n")
+
+	g, err := Correlate(context.Background(), strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("unexpected error correlating dump: %s", err)
+	}
+
+	if diff := cmp.Diff([]int{1, 4}, g.Documents()); diff != "" {
+		t.Errorf("unexpected documents (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]int{1, 4}, g.DocumentsByURI("file:///test/foo.go")); diff != "" {
+		t.Errorf("unexpected documents by URI (-want +got):\n%s", diff)
+	}
+
+	if documentID, ok := g.DocumentByURI("file:///test/foo.go"); !ok || documentID != 1 {
+		t.Errorf("unexpected document. want=%d have=%d", 1, documentID)
+	}
+}
+
+func TestCorrelateReferenceResults(t *testing.T) {
+	input := strings.Join([]string{
+		`{"id": 1, "type": "vertex", "label": "document", "uri": "file:///test/foo.go"}`,
+		`{"id": 2, "type": "vertex", "label": "range", "start": {"line": 1, "character": 5}, "end": {"line": 1, "character": 8}}`,
+		`{"id": 3, "type": "vertex", "label": "range", "start": {"line": 4, "character": 2}, "end": {"line": 4, "character": 5}}`,
+		`{"id": 4, "type": "edge", "label": "contains", "outV": 1, "inVs": [2, 3]}`,
+		`{"id": 5, "type": "vertex", "label": "referenceResult"}`,
+		`{"id": 6, "type": "vertex", "label": "referenceResult"}`,
+		`{"id": 7, "type": "edge", "label": "textDocument/references", "outV": 2, "inV": 5}`,
+		`{"id": 8, "type": "edge", "label": "item", "outV": 5, "inVs": [2], "document": 1, "property": "references"}`,
+		`{"id": 9, "type": "edge", "label": "item", "outV": 5, "inVs": [6], "document": 1, "property": "referenceResults"}`,
+		`{"id": 10, "type": "edge", "label": "item", "outV": 6, "inVs": [3, 2], "document": 1, "property": "references"}`,
+	}, "\n")
+
+	g, err := Correlate(context.Background(), strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("unexpected error correlating dump: %s", err)
+	}
+
+	var rangeIDs []int
+	for _, location := range g.References(2) {
+		rangeIDs = append(rangeIDs, location.RangeID)
+	}
+	if diff := cmp.Diff([]int{2, 3}, rangeIDs); diff != "" {
+		t.Errorf("unexpected reference ranges (-want +got):\n%s", diff)
+	}
+}
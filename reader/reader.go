@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"fmt"
 	"io"
 	"runtime"
 	"sync"
@@ -12,15 +13,74 @@ import (
 type Pair struct {
 	Element Element
 	Err     error
+
+	// Line is the 1-indexed line of the input from which the element was read.
+	Line int
+
+	// Offset is the byte offset of the start of the line within the (decompressed) input.
+	Offset int64
+
+	// Length is the number of bytes of the line, excluding the line terminator.
+	Length int
+}
+
+// UnmarshalError wraps an error that occurred while unmarshalling a line of the input.
+type UnmarshalError struct {
+	Line   int
+	Offset int64
+	Err    error
+}
+
+func (e *UnmarshalError) Error() string {
+	return fmt.Sprintf("line %d (offset %d): %s", e.Line, e.Offset, e.Err)
+}
+
+func (e *UnmarshalError) Unwrap() error {
+	return e.Err
+}
+
+// ReadOption configures the behavior of Read.
+type ReadOption func(o *readOptions)
+
+type readOptions struct {
+	rawLines bool
+}
+
+// WithRawLines retains a copy of each line of the input in the Raw field of the element
+// read from that line. This allows elements that are not modified by a transformation to
+// be re-emitted byte-for-byte, including properties unknown to this package.
+func WithRawLines() ReadOption {
+	return func(o *readOptions) {
+		o.rawLines = true
+	}
 }
 
 // Read reads the given content as line-separated JSON objects and returns a channel of Pair values for each
-// non-empty line.
-func Read(ctx context.Context, r io.Reader) <-chan Pair {
+// non-empty line. Gzip-compressed content is detected and decompressed transparently.
+func Read(ctx context.Context, r io.Reader, options ...ReadOption) <-chan Pair {
+	var opts readOptions
+	for _, option := range options {
+		option(&opts)
+	}
+
+	r, err := Decompress(r)
+	if err != nil {
+		pairCh := make(chan Pair, 1)
+		pairCh <- Pair{Err: err}
+		close(pairCh)
+		return pairCh
+	}
+
 	interner := NewInterner()
 
 	return readLines(ctx, r, func(line []byte) (Element, error) {
-		return unmarshalElement(interner, line)
+		element, err := unmarshalElement(interner, line)
+		if err == nil && opts.rawLines {
+			// The line is backed by a pooled buffer that is reused once unmarshalled
+			element.Raw = append([]byte(nil), line...)
+		}
+
+		return element, err
 	})
 }
 
@@ -37,28 +97,53 @@ const ChannelBufferSize = 512
 // NumUnmarshalGoRoutines is the number of goroutines launched to unmarshal individual lines.
 var NumUnmarshalGoRoutines = runtime.GOMAXPROCS(0)
 
+// sourceLine is a copy of a non-empty line of the input along with its location.
+type sourceLine struct {
+	buf    *bytes.Buffer
+	line   int
+	offset int64
+}
+
 // readLines reads the given content as line-separated objects which are unmarshallable by the given function
 // and returns a channel of Pair values for each non-empty line.
 func readLines(ctx context.Context, r io.Reader, unmarshal func(line []byte) (Element, error)) <-chan Pair {
+	// Track the number of bytes consumed by each token (including the line terminator)
+	// so that we can report the byte offset of each line.
+	var advance int
 	scanner := bufio.NewScanner(r)
-	scanner.Split(bufio.ScanLines)
+	scanner.Split(func(data []byte, atEOF bool) (int, []byte, error) {
+		n, token, err := bufio.ScanLines(data, atEOF)
+		if token != nil {
+			advance = n
+		}
+		return n, token, err
+	})
 	scanner.Buffer(make([]byte, LineBufferSize), LineBufferSize)
 
 	// Pool of buffers used to transfer copies of the scanner slice to unmarshal workers
 	pool := sync.Pool{New: func() interface{} { return new(bytes.Buffer) }}
 
+	// The location following the last line read by the scanner. These values are
+	// written by the reader routine and read only once lineCh has been closed.
+	var lineNumber int
+	var offset int64
+
 	// Read the document in a separate go-routine.
-	lineCh := make(chan *bytes.Buffer, ChannelBufferSize)
+	lineCh := make(chan sourceLine, ChannelBufferSize)
 	go func() {
 		defer close(lineCh)
 
 		for scanner.Scan() {
+			lineNumber++
+			lineOffset := offset
+			offset += int64(advance)
+
 			if line := scanner.Bytes(); len(line) != 0 {
 				buf := pool.Get().(*bytes.Buffer)
 				_, _ = buf.Write(line)
 
 				select {
-				case lineCh <- buf:
+				case lineCh <- sourceLine{buf: buf, line: lineNumber, offset: lineOffset}:
 				case <-ctx.Done():
 					return
 				}
@@ -81,7 +166,7 @@ func readLines(ctx context.Context, r io.Reader, unmarshal func(line []byte) (El
 		defer close(signal)
 
 		// The input slice
-		lines := make([]*bytes.Buffer, NumUnmarshalGoRoutines)
+		lines := make([]sourceLine, NumUnmarshalGoRoutines)
 
 		// The result slice
 		pairs := make([]Pair, NumUnmarshalGoRoutines)
@@ -89,9 +174,16 @@ func readLines(ctx context.Context, r io.Reader, unmarshal func(line []byte) (El
 		for i := 0; i < NumUnmarshalGoRoutines; i++ {
 			go func() {
 				for idx := range work {
-					element, err := unmarshal(lines[idx].Bytes())
+					element, err := unmarshal(lines[idx].buf.Bytes())
+					if err != nil {
+						err = &UnmarshalError{Line: lines[idx].line, Offset: lines[idx].offset, Err: err}
+					}
+
 					pairs[idx].Element = element
 					pairs[idx].Err = err
+					pairs[idx].Line = lines[idx].line
+					pairs[idx].Offset = lines[idx].offset
+					pairs[idx].Length = lines[idx].buf.Len()
 					signal <- struct{}{}
 				}
 			}()
@@ -123,8 +215,8 @@ func readLines(ctx context.Context, r io.Reader, unmarshal func(line []byte) (El
 
 			// Return each buffer to the pool for reuse
 			for j := 0; j < i; j++ {
-				lines[j].Reset()
-				pool.Put(lines[j])
+				lines[j].buf.Reset()
+				pool.Put(lines[j].buf)
 			}
 
 			// Read the result array in order. If the caller context has completed,
@@ -141,7 +233,7 @@ func readLines(ctx context.Context, r io.Reader, unmarshal func(line []byte) (El
 
 		// If there was an error reading from the source, output it here
 		if err := scanner.Err(); err != nil {
-			pairCh <- Pair{Err: err}
+			pairCh <- Pair{Err: err, Line: lineNumber + 1, Offset: offset}
 		}
 	}()
 
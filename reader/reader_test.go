@@ -2,7 +2,9 @@ package reader
 
 import (
 	"bytes"
+	"compress/gzip"
 	"context"
+	"errors"
 	"strconv"
 	"testing"
 
@@ -45,3 +47,113 @@ func TestReadLines(t *testing.T) {
 		t.Errorf("unexpected ids (-want +got):\n%s", diff)
 	}
 }
+
+func TestReadLinesLocations(t *testing.T) {
+	content := "1\n\n22\r\nx\n\n333\n"
+
+	unmarshal := func(line []byte) (Element, error) {
+		id, err := strconv.Atoi(string(line))
+		if err != nil {
+			return Element{}, err
+		}
+
+		return Element{ID: id}, nil
+	}
+
+	type location struct {
+		ID     int
+		Line   int
+		Offset int64
+		Length int
+		Failed bool
+	}
+
+	var locations []location
+	for pair := range readLines(context.Background(), bytes.NewReader([]byte(content)), unmarshal) {
+		if pair.Err != nil {
+			var unmarshalErr *UnmarshalError
+			if !errors.As(pair.Err, &unmarshalErr) {
+				t.Fatalf("unexpected error type: %T", pair.Err)
+			}
+			if unmarshalErr.Line != pair.Line || unmarshalErr.Offset != pair.Offset {
+				t.Errorf("unexpected error location: %s", unmarshalErr)
+			}
+		}
+
+		locations = append(locations, location{
+			ID:     pair.Element.ID,
+			Line:   pair.Line,
+			Offset: pair.Offset,
+			Length: pair.Length,
+			Failed: pair.Err != nil,
+		})
+	}
+
+	expectedLocations := []location{
+		{ID: 1, Line: 1, Offset: 0, Length: 1},
+		{ID: 22, Line: 3, Offset: 3, Length: 2},
+		{ID: 0, Line: 4, Offset: 7, Length: 1, Failed: true},
+		{ID: 333, Line: 6, Offset: 10, Length: 3},
+	}
+	if diff := cmp.Diff(expectedLocations, locations); diff != "" {
+		t.Errorf("unexpected locations (-want +got):\n%s", diff)
+	}
+}
+
+func TestReadGzip(t *testing.T) {
+	content := "" +
+		`{"id": 1, "type": "vertex", "label": "project", "kind": "go"}` + "\n" +
+		`{"id": 2, "type": "vertex", "label": "resultSet"}` + "\n"
+
+	var buf bytes.Buffer
+	gzipWriter := gzip.NewWriter(&buf)
+	if _, err := gzipWriter.Write([]byte(content)); err != nil {
+		t.Fatalf("unexpected error writing gzip content: %s", err)
+	}
+	if err := gzipWriter.Close(); err != nil {
+		t.Fatalf("unexpected error closing gzip writer: %s", err)
+	}
+
+	var elements []Element
+	for pair := range Read(context.Background(), &buf) {
+		if pair.Err != nil {
+			t.Fatalf("unexpected error: %s", pair.Err)
+		}
+
+		elements = append(elements, pair.Element)
+	}
+
+	expectedElements := []Element{
+		{ID: 1, Type: "vertex", Label: "project", Payload: Project{Kind: "go"}},
+		{ID: 2, Type: "vertex", Label: "resultSet"},
+	}
+	if diff := cmp.Diff(expectedElements, elements); diff != "" {
+		t.Errorf("unexpected elements (-want +got):\n%s", diff)
+	}
+}
+
+func TestReadRawLines(t *testing.T) {
+	lines := []string{
+		`{"id": 1, "type": "vertex", "label": "document", "uri": "file:///foo.go", "x-vendor": {"hash": "abc"}}`,
+		`{"id": 2, "type": "vertex", "label": "range", "start": {"line": 1, "character": 2}, "end": {"line": 1, "character": 5}}`,
+	}
+
+	var raw []string
+	for pair := range Read(context.Background(), bytes.NewReader([]byte(lines[0]+"\n"+lines[1]+"\n")), WithRawLines()) {
+		if pair.Err != nil {
+			t.Fatalf("unexpected error: %s", pair.Err)
+		}
+
+		raw = append(raw, string(pair.Element.Raw))
+	}
+
+	if diff := cmp.Diff(lines, raw); diff != "" {
+		t.Errorf("unexpected raw lines (-want +got):\n%s", diff)
+	}
+
+	for pair := range Read(context.Background(), bytes.NewReader([]byte(lines[0]))) {
+		if pair.Element.Raw != nil {
+			t.Errorf("unexpected raw line without option: %s", pair.Element.Raw)
+		}
+	}
+}
@@ -5,6 +5,12 @@ type Element struct {
 	Type    string
 	Label   string
 	Payload interface{}
+
+	// Raw is a copy of the line from which the element was read, including any
+	// properties not decoded into the payload. It is populated only when the input
+	// is read with the WithRawLines option. Identifiers within Raw are the original
+	// identifiers of the input, not the interned identifiers of the element.
+	Raw []byte
 }
 
 type Edge struct {
@@ -19,11 +25,31 @@ type MetaData struct {
 	ProjectRoot string
 }
 
+type Document struct {
+	// URI is the URI of the document as written in the dump.
+	URI string
+
+	// Path is the percent-decoded filesystem path of a document with a file URI, or the
+	// decoded path relative to the project root of a document with a relative URI. It is
+	// empty for documents with any other kind of URI.
+	Path string
+}
+
 type Range struct {
 	StartLine      int
 	StartCharacter int
 	EndLine        int
 	EndCharacter   int
+	Tag            *RangeTag
+}
+
+type RangeTag struct {
+	Type       string
+	Text       string
+	Kind       int
+	Deprecated bool
+	Detail     string
+	FullRange  *Range
 }
 
 type ResultSet struct{}
@@ -49,3 +75,52 @@ type Diagnostic struct {
 	EndLine        int
 	EndCharacter   int
 }
+
+type Project struct {
+	Kind string
+}
+
+type Event struct {
+	Kind  string
+	Scope string
+	Data  int
+}
+
+type DocumentSymbol struct {
+	Name                    string
+	Detail                  string
+	Kind                    int
+	Tags                    []int
+	Deprecated              bool
+	StartLine               int
+	StartCharacter          int
+	EndLine                 int
+	EndCharacter            int
+	SelectionStartLine      int
+	SelectionStartCharacter int
+	SelectionEndLine        int
+	SelectionEndCharacter   int
+	Children                []DocumentSymbol
+}
+
+type RangeBasedDocumentSymbol struct {
+	ID       int
+	Children []RangeBasedDocumentSymbol
+}
+
+type FoldingRange struct {
+	StartLine      int
+	StartCharacter *int
+	EndLine        int
+	EndCharacter   *int
+	Kind           string
+}
+
+type DocumentLink struct {
+	Target         string
+	Tooltip        string
+	StartLine      int
+	StartCharacter int
+	EndLine        int
+	EndCharacter   int
+}
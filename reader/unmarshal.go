@@ -37,6 +37,8 @@ func unmarshalElement(interner *Interner, line []byte) (_ Element, err error) {
 	} else if element.Type == "vertex" {
 		if unmarshaler, ok := vertexUnmarshalers[element.Label]; ok {
 			element.Payload, err = unmarshaler(line)
+		} else if unmarshaler, ok := internedVertexUnmarshalers[element.Label]; ok {
+			element.Payload, err = unmarshaler(interner, line)
 		}
 	}
 
@@ -115,14 +117,27 @@ func unmarshalEdgeFast(line []byte) (Edge, bool) {
 	}, true
 }
 
+// vertexUnmarshalers decode the payload of vertices by label. Vertices such as resultSet,
+// definitionResult, declarationResult, and implementationResult carry no properties of
+// their own (their contents are attached via item edges) and have no entry here.
 var vertexUnmarshalers = map[string]func(line []byte) (interface{}, error){
 	"metaData":           unmarshalMetaData,
+	"project":            unmarshalProject,
 	"document":           unmarshalDocument,
 	"range":              unmarshalRange,
 	"hoverResult":        unmarshalHover,
 	"moniker":            unmarshalMoniker,
 	"packageInformation": unmarshalPackageInformation,
 	"diagnosticResult":   unmarshalDiagnosticResult,
+	"foldingRangeResult": unmarshalFoldingRangeResult,
+	"documentLinkResult": unmarshalDocumentLinkResult,
+}
+
+// internedVertexUnmarshalers decode the payload of vertices that refer to the identifiers
+// of other elements, which must be submitted to the same interner as the element envelopes.
+var internedVertexUnmarshalers = map[string]func(interner *Interner, line []byte) (interface{}, error){
+	"$event":               unmarshalEvent,
+	"documentSymbolResult": unmarshalDocumentSymbolResult,
 }
 
 func unmarshalMetaData(line []byte) (interface{}, error) {
@@ -140,6 +155,41 @@ func unmarshalMetaData(line []byte) (interface{}, error) {
 	}, nil
 }
 
+func unmarshalProject(line []byte) (interface{}, error) {
+	var payload struct {
+		Kind string `json:"kind"`
+	}
+	if err := unmarshaller.Unmarshal(line, &payload); err != nil {
+		return nil, err
+	}
+
+	return Project{
+		Kind: payload.Kind,
+	}, nil
+}
+
+func unmarshalEvent(interner *Interner, line []byte) (interface{}, error) {
+	var payload struct {
+		Kind  string          `json:"kind"`
+		Scope string          `json:"scope"`
+		Data  json.RawMessage `json:"data"`
+	}
+	if err := unmarshaller.Unmarshal(line, &payload); err != nil {
+		return nil, err
+	}
+
+	data, err := internRaw(interner, payload.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	return Event{
+		Kind:  payload.Kind,
+		Scope: payload.Scope,
+		Data:  data,
+	}, nil
+}
+
 func unmarshalDocument(line []byte) (interface{}, error) {
 	var payload struct {
 		URI string `json:"uri"`
@@ -148,7 +198,7 @@ func unmarshalDocument(line []byte) (interface{}, error) {
 		return nil, err
 	}
 
-	return payload.URI, nil
+	return Document{URI: payload.URI, Path: documentPath(payload.URI)}, nil
 }
 
 func unmarshalRange(line []byte) (interface{}, error) {
@@ -156,19 +206,53 @@ func unmarshalRange(line []byte) (interface{}, error) {
 		Line      int `json:"line"`
 		Character int `json:"character"`
 	}
+	type _range struct {
+		Start _position `json:"start"`
+		End   _position `json:"end"`
+	}
+	type _tag struct {
+		Type       string  `json:"type"`
+		Text       string  `json:"text"`
+		Kind       int     `json:"kind"`
+		Deprecated bool    `json:"deprecated"`
+		Detail     string  `json:"detail"`
+		FullRange  *_range `json:"fullRange"`
+	}
 	var payload struct {
 		Start _position `json:"start"`
 		End   _position `json:"end"`
+		Tag   *_tag     `json:"tag"`
 	}
 	if err := unmarshaller.Unmarshal(line, &payload); err != nil {
 		return nil, err
 	}
 
+	var tag *RangeTag
+	if payload.Tag != nil {
+		tag = &RangeTag{
+			Type:       payload.Tag.Type,
+			Text:       payload.Tag.Text,
+			Kind:       payload.Tag.Kind,
+			Deprecated: payload.Tag.Deprecated,
+			Detail:     payload.Tag.Detail,
+		}
+
+		if fullRange := payload.Tag.FullRange; fullRange != nil {
+			tag.FullRange = &Range{
+				StartLine:      fullRange.Start.Line,
+				StartCharacter: fullRange.Start.Character,
+				EndLine:        fullRange.End.Line,
+				EndCharacter:   fullRange.End.Character,
+			}
+		}
+	}
+
 	return Range{
 		StartLine:      payload.Start.Line,
 		StartCharacter: payload.Start.Character,
 		EndLine:        payload.End.Line,
 		EndCharacter:   payload.End.Character,
+		Tag:            tag,
 	}, nil
 }
 
@@ -316,6 +400,158 @@ func unmarshalDiagnosticResult(line []byte) (interface{}, error) {
 	return diagnostics, nil
 }
 
+func unmarshalFoldingRangeResult(line []byte) (interface{}, error) {
+	type _result struct {
+		StartLine      int    `json:"startLine"`
+		StartCharacter *int   `json:"startCharacter"`
+		EndLine        int    `json:"endLine"`
+		EndCharacter   *int   `json:"endCharacter"`
+		Kind           string `json:"kind"`
+	}
+	var payload struct {
+		Results []_result `json:"result"`
+	}
+	if err := unmarshaller.Unmarshal(line, &payload); err != nil {
+		return nil, err
+	}
+
+	var foldingRanges []FoldingRange
+	for _, result := range payload.Results {
+		foldingRanges = append(foldingRanges, FoldingRange{
+			StartLine:      result.StartLine,
+			StartCharacter: result.StartCharacter,
+			EndLine:        result.EndLine,
+			EndCharacter:   result.EndCharacter,
+			Kind:           result.Kind,
+		})
+	}
+
+	return foldingRanges, nil
+}
+
+func unmarshalDocumentLinkResult(line []byte) (interface{}, error) {
+	type _position struct {
+		Line      int `json:"line"`
+		Character int `json:"character"`
+	}
+	type _range struct {
+		Start _position `json:"start"`
+		End   _position `json:"end"`
+	}
+	type _result struct {
+		Range   _range `json:"range"`
+		Target  string `json:"target"`
+		Tooltip string `json:"tooltip"`
+	}
+	var payload struct {
+		Results []_result `json:"result"`
+	}
+	if err := unmarshaller.Unmarshal(line, &payload); err != nil {
+		return nil, err
+	}
+
+	var documentLinks []DocumentLink
+	for _, result := range payload.Results {
+		documentLinks = append(documentLinks, DocumentLink{
+			Target:         result.Target,
+			Tooltip:        result.Tooltip,
+			StartLine:      result.Range.Start.Line,
+			StartCharacter: result.Range.Start.Character,
+			EndLine:        result.Range.End.Line,
+			EndCharacter:   result.Range.End.Character,
+		})
+	}
+
+	return documentLinks, nil
+}
+
+type _documentSymbolPosition struct {
+	Line      int `json:"line"`
+	Character int `json:"character"`
+}
+
+type _documentSymbolRange struct {
+	Start _documentSymbolPosition `json:"start"`
+	End   _documentSymbolPosition `json:"end"`
+}
+
+// _documentSymbol is the union of an LSP DocumentSymbol and an LSIF RangeBasedDocumentSymbol.
+// The latter is distinguished by the presence of an id property.
+type _documentSymbol struct {
+	ID             json.RawMessage      `json:"id"`
+	Name           string               `json:"name"`
+	Detail         string               `json:"detail"`
+	Kind           int                  `json:"kind"`
+	Tags           []int                `json:"tags"`
+	Deprecated     bool                 `json:"deprecated"`
+	Range          _documentSymbolRange `json:"range"`
+	SelectionRange _documentSymbolRange `json:"selectionRange"`
+	Children       []_documentSymbol    `json:"children"`
+}
+
+// unmarshalDocumentSymbolResult returns either a slice of DocumentSymbol values or a slice of
+// RangeBasedDocumentSymbol values, depending on the form of the vertex's result property.
+func unmarshalDocumentSymbolResult(interner *Interner, line []byte) (interface{}, error) {
+	var payload struct {
+		Results []_documentSymbol `json:"result"`
+	}
+	if err := unmarshaller.Unmarshal(line, &payload); err != nil {
+		return nil, err
+	}
+
+	if len(payload.Results) > 0 && len(bytes.TrimSpace(payload.Results[0].ID)) > 0 {
+		return convertRangeBasedDocumentSymbols(interner, payload.Results)
+	}
+
+	return convertDocumentSymbols(payload.Results), nil
+}
+
+func convertDocumentSymbols(results []_documentSymbol) []DocumentSymbol {
+	var documentSymbols []DocumentSymbol
+	for _, result := range results {
+		documentSymbols = append(documentSymbols, DocumentSymbol{
+			Name:                    result.Name,
+			Detail:                  result.Detail,
+			Kind:                    result.Kind,
+			Tags:                    result.Tags,
+			Deprecated:              result.Deprecated,
+			StartLine:               result.Range.Start.Line,
+			StartCharacter:          result.Range.Start.Character,
+			EndLine:                 result.Range.End.Line,
+			EndCharacter:            result.Range.End.Character,
+			SelectionStartLine:      result.SelectionRange.Start.Line,
+			SelectionStartCharacter: result.SelectionRange.Start.Character,
+			SelectionEndLine:        result.SelectionRange.End.Line,
+			SelectionEndCharacter:   result.SelectionRange.End.Character,
+			Children:                convertDocumentSymbols(result.Children),
+		})
+	}
+
+	return documentSymbols
+}
+
+func convertRangeBasedDocumentSymbols(interner *Interner, results []_documentSymbol) ([]RangeBasedDocumentSymbol, error) {
+	var documentSymbols []RangeBasedDocumentSymbol
+	for _, result := range results {
+		id, err := internRaw(interner, result.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		children, err := convertRangeBasedDocumentSymbols(interner, result.Children)
+		if err != nil {
+			return nil, err
+		}
+
+		documentSymbols = append(documentSymbols, RangeBasedDocumentSymbol{
+			ID:       id,
+			Children: children,
+		})
+	}
+
+	return documentSymbols, nil
+}
+
 type StringOrInt string
 
 func (id *StringOrInt) UnmarshalJSON(raw []byte) error {
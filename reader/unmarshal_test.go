@@ -2,6 +2,7 @@ package reader
 
 import (
 	"fmt"
+	"path/filepath"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -89,13 +90,60 @@ func TestUnmarshalMetaData(t *testing.T) {
 }
 
 func TestUnmarshalDocument(t *testing.T) {
-	uri, err := unmarshalDocument([]byte(`{"id": "02", "type": "vertex", "label": "document", "uri": "file:///test/root/foo.go"}`))
+	document, err := unmarshalDocument([]byte(`{"id": "02", "type": "vertex", "label": "document", "uri": "file:///test/root/foo%20bar.go"}`))
 	if err != nil {
 		t.Fatalf("unexpected error unmarshalling document data: %s", err)
 	}
 
-	if diff := cmp.Diff("file:///test/root/foo.go", uri); diff != "" {
-		t.Errorf("unexpected uri (-want +got):\n%s", diff)
+	expectedDocument := Document{
+		URI:  "file:///test/root/foo%20bar.go",
+		Path: "/test/root/foo bar.go",
+	}
+	if diff := cmp.Diff(expectedDocument, document); diff != "" {
+		t.Errorf("unexpected document (-want +got):\n%s", diff)
+	}
+}
+
+func TestDocumentPath(t *testing.T) {
+	testCases := map[string]string{
+		"file:///test/root/foo.go":          "/test/root/foo.go",
+		"file:///test/my%20root/%231.go":    "/test/my root/#1.go",
+		"file:///test/h%C3%A9llo.go":        "/test/héllo.go",
+		"file:///C:/Users/me/foo%20bar.go":  filepath.FromSlash("C:/Users/me/foo bar.go"),
+		"file://server/share/foo.go":        filepath.FromSlash("//server/share/foo.go"),
+		"pkg/foo%20bar.go":                  filepath.FromSlash("pkg/foo bar.go"),
+		"https://example.com/repo/pkg/x.go": "",
+	}
+
+	for uri, expected := range testCases {
+		if path := documentPath(uri); path != expected {
+			t.Errorf("unexpected path for %s. want=%q have=%q", uri, expected, path)
+		}
+	}
+}
+
+func TestRelativePath(t *testing.T) {
+	testCases := []struct {
+		projectRoot string
+		uri         string
+		path        string
+		ok          bool
+	}{
+		{"file:///test/root", "file:///test/root/pkg/foo.go", "pkg/foo.go", true},
+		{"file:///test/root/", "file:///test/root/pkg/foo.go", "pkg/foo.go", true},
+		{"file:///test/my%20root", "file:///test/my%20root/foo%20bar.go", "foo bar.go", true},
+		{"file:///test/root", "pkg/foo%20bar.go", "pkg/foo bar.go", true},
+		{"file:///test/root", "file:///test/other/foo.go", "", false},
+		{"file:///test/root", "../other/foo.go", "", false},
+		{"", "pkg/foo%20bar.go", "pkg/foo bar.go", true},
+		{"", "file:///test/root/foo.go", "", false},
+	}
+
+	for _, testCase := range testCases {
+		path, ok := RelativePath(testCase.projectRoot, testCase.uri)
+		if path != testCase.path || ok != testCase.ok {
+			t.Errorf("unexpected path for %s in %s. want=%q,%v have=%q,%v", testCase.uri, testCase.projectRoot, testCase.path, testCase.ok, path, ok)
+		}
 	}
 }
 
@@ -116,6 +164,35 @@ func TestUnmarshalRange(t *testing.T) {
 	}
 }
 
+func TestUnmarshalRangeWithTag(t *testing.T) {
+	r, err := unmarshalRange([]byte(`{"id": "04", "type": "vertex", "label": "range", "start": {"line": 1, "character": 5}, "end": {"line": 1, "character": 8}, "tag": {"type": "definition", "text": "Foo", "kind": 12, "detail": "func Foo()", "fullRange": {"start": {"line": 1, "character": 0}, "end": {"line": 3, "character": 1}}}}`))
+	if err != nil {
+		t.Fatalf("unexpected error unmarshalling range data: %s", err)
+	}
+
+	expectedRange := Range{
+		StartLine:      1,
+		StartCharacter: 5,
+		EndLine:        1,
+		EndCharacter:   8,
+		Tag: &RangeTag{
+			Type:   "definition",
+			Text:   "Foo",
+			Kind:   12,
+			Detail: "func Foo()",
+			FullRange: &Range{
+				StartLine:      1,
+				StartCharacter: 0,
+				EndLine:        3,
+				EndCharacter:   1,
+			},
+		},
+	}
+	if diff := cmp.Diff(expectedRange, r); diff != "" {
+		t.Errorf("unexpected range (-want +got):\n%s", diff)
+	}
+}
+
 func TestUnmarshalHover(t *testing.T) {
 	testCases := []struct {
 		contents      string
@@ -208,3 +285,138 @@ func TestUnmarshalDiagnosticResult(t *testing.T) {
 		t.Errorf("unexpected diagnostic result (-want +got):\n%s", diff)
 	}
 }
+
+func TestUnmarshalProject(t *testing.T) {
+	project, err := unmarshalProject([]byte(`{"id": "03", "type": "vertex", "label": "project", "kind": "go"}`))
+	if err != nil {
+		t.Fatalf("unexpected error unmarshalling project data: %s", err)
+	}
+
+	if diff := cmp.Diff(Project{Kind: "go"}, project); diff != "" {
+		t.Errorf("unexpected project (-want +got):\n%s", diff)
+	}
+}
+
+func TestUnmarshalEvent(t *testing.T) {
+	event, err := unmarshalEvent(NewInterner(), []byte(`{"id": "05", "type": "vertex", "label": "$event", "kind": "begin", "scope": "document", "data": "02"}`))
+	if err != nil {
+		t.Fatalf("unexpected error unmarshalling event data: %s", err)
+	}
+
+	expectedEvent := Event{
+		Kind:  "begin",
+		Scope: "document",
+		Data:  2,
+	}
+	if diff := cmp.Diff(expectedEvent, event); diff != "" {
+		t.Errorf("unexpected event (-want +got):\n%s", diff)
+	}
+}
+
+func TestUnmarshalDocumentSymbolResult(t *testing.T) {
+	documentSymbolResult, err := unmarshalDocumentSymbolResult(NewInterner(), []byte(`{"id": 30, "type": "vertex", "label": "documentSymbolResult", "result": [{"name": "Foo", "detail": "type Foo struct", "kind": 23, "range": {"start": {"line": 1, "character": 0}, "end": {"line": 5, "character": 1}}, "selectionRange": {"start": {"line": 1, "character": 5}, "end": {"line": 1, "character": 8}}, "children": [{"name": "Bar", "kind": 8, "tags": [1], "range": {"start": {"line": 2, "character": 1}, "end": {"line": 2, "character": 8}}, "selectionRange": {"start": {"line": 2, "character": 1}, "end": {"line": 2, "character": 4}}}]}]}`))
+	if err != nil {
+		t.Fatalf("unexpected error unmarshalling document symbol result data: %s", err)
+	}
+
+	expectedDocumentSymbolResult := []DocumentSymbol{
+		{
+			Name:                    "Foo",
+			Detail:                  "type Foo struct",
+			Kind:                    23,
+			StartLine:               1,
+			StartCharacter:          0,
+			EndLine:                 5,
+			EndCharacter:            1,
+			SelectionStartLine:      1,
+			SelectionStartCharacter: 5,
+			SelectionEndLine:        1,
+			SelectionEndCharacter:   8,
+			Children: []DocumentSymbol{
+				{
+					Name:                    "Bar",
+					Kind:                    8,
+					Tags:                    []int{1},
+					StartLine:               2,
+					StartCharacter:          1,
+					EndLine:                 2,
+					EndCharacter:            8,
+					SelectionStartLine:      2,
+					SelectionStartCharacter: 1,
+					SelectionEndLine:        2,
+					SelectionEndCharacter:   4,
+				},
+			},
+		},
+	}
+	if diff := cmp.Diff(expectedDocumentSymbolResult, documentSymbolResult); diff != "" {
+		t.Errorf("unexpected document symbol result (-want +got):\n%s", diff)
+	}
+}
+
+func TestUnmarshalRangeBasedDocumentSymbolResult(t *testing.T) {
+	documentSymbolResult, err := unmarshalDocumentSymbolResult(NewInterner(), []byte(`{"id": 30, "type": "vertex", "label": "documentSymbolResult", "result": [{"id": "04", "children": [{"id": "07"}, {"id": "08"}]}, {"id": "09"}]}`))
+	if err != nil {
+		t.Fatalf("unexpected error unmarshalling document symbol result data: %s", err)
+	}
+
+	expectedDocumentSymbolResult := []RangeBasedDocumentSymbol{
+		{
+			ID: 4,
+			Children: []RangeBasedDocumentSymbol{
+				{ID: 7},
+				{ID: 8},
+			},
+		},
+		{ID: 9},
+	}
+	if diff := cmp.Diff(expectedDocumentSymbolResult, documentSymbolResult); diff != "" {
+		t.Errorf("unexpected document symbol result (-want +got):\n%s", diff)
+	}
+}
+
+func TestUnmarshalFoldingRangeResult(t *testing.T) {
+	foldingRangeResult, err := unmarshalFoldingRangeResult([]byte(`{"id": 31, "type": "vertex", "label": "foldingRangeResult", "result": [{"startLine": 2, "startCharacter": 10, "endLine": 8, "kind": "region"}, {"startLine": 0, "endLine": 1, "kind": "comment"}]}`))
+	if err != nil {
+		t.Fatalf("unexpected error unmarshalling folding range result data: %s", err)
+	}
+
+	startCharacter := 10
+	expectedFoldingRangeResult := []FoldingRange{
+		{
+			StartLine:      2,
+			StartCharacter: &startCharacter,
+			EndLine:        8,
+			Kind:           "region",
+		},
+		{
+			StartLine: 0,
+			EndLine:   1,
+			Kind:      "comment",
+		},
+	}
+	if diff := cmp.Diff(expectedFoldingRangeResult, foldingRangeResult); diff != "" {
+		t.Errorf("unexpected folding range result (-want +got):\n%s", diff)
+	}
+}
+
+func TestUnmarshalDocumentLinkResult(t *testing.T) {
+	documentLinkResult, err := unmarshalDocumentLinkResult([]byte(`{"id": 32, "type": "vertex", "label": "documentLinkResult", "result": [{"range": {"start": {"line": 3, "character": 8}, "end": {"line": 3, "character": 20}}, "target": "https://example.com", "tooltip": "example"}]}`))
+	if err != nil {
+		t.Fatalf("unexpected error unmarshalling document link result data: %s", err)
+	}
+
+	expectedDocumentLinkResult := []DocumentLink{
+		{
+			Target:         "https://example.com",
+			Tooltip:        "example",
+			StartLine:      3,
+			StartCharacter: 8,
+			EndLine:        3,
+			EndCharacter:   20,
+		},
+	}
+	if diff := cmp.Diff(expectedDocumentLinkResult, documentLinkResult); diff != "" {
+		t.Errorf("unexpected document link result (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,77 @@
+package reader
+
+import (
+	"net/url"
+	"path/filepath"
+	"strings"
+)
+
+// RelativePath returns the decoded, slash-separated path of the given document URI relative
+// to the given project root. Relative URI references are resolved against the project root,
+// or decoded as-is if the project root is empty. False is returned for malformed URIs and for
+// absolute URIs outside of the project root.
+func RelativePath(projectRoot, uri string) (string, bool) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return "", false
+	}
+
+	if projectRoot == "" {
+		if u.IsAbs() {
+			return "", false
+		}
+
+		return u.Path, true
+	}
+
+	root, err := url.Parse(WithTrailingSlash(projectRoot))
+	if err != nil {
+		return "", false
+	}
+
+	u = root.ResolveReference(u)
+	if u.Scheme != root.Scheme || u.Host != root.Host || !strings.HasPrefix(u.Path, root.Path) {
+		return "", false
+	}
+
+	return strings.TrimPrefix(u.Path, root.Path), true
+}
+
+// WithTrailingSlash returns the given URI with a trailing slash so that it can be used as
+// a prefix of the URIs beneath it. The empty URI is returned unchanged.
+func WithTrailingSlash(uri string) string {
+	if uri == "" || strings.HasSuffix(uri, "/") {
+		return uri
+	}
+
+	return uri + "/"
+}
+
+// documentPath returns the decoded filesystem path of the given file URI or the decoded
+// path of the given relative URI reference. An empty string is returned for URIs with any
+// other scheme and for malformed URIs.
+func documentPath(uri string) string {
+	if path, ok := RelativePath("", uri); ok {
+		return filepath.FromSlash(path)
+	}
+
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "file" {
+		return ""
+	}
+
+	path := u.Path
+	if len(path) >= 3 && path[0] == '/' && isDriveLetter(path[1]) && path[2] == ':' {
+		// Windows path with a drive letter (file:///C:/...)
+		path = path[1:]
+	} else if u.Host != "" && u.Host != "localhost" {
+		// UNC path (file://server/share/...)
+		path = "//" + u.Host + path
+	}
+
+	return filepath.FromSlash(path)
+}
+
+func isDriveLetter(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
@@ -0,0 +1,286 @@
+// Package transform rewrites LSIF dumps as a stream, applying a sequence of transformers
+// to each element. Elements that are not modified are written byte-for-byte as they were
+// read, including any properties unknown to this module.
+package transform
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/sourcegraph/lsif-protocol/internal/object"
+	"github.com/sourcegraph/lsif-protocol/reader"
+	"github.com/sourcegraph/lsif-protocol/writer"
+)
+
+var marshaller = jsoniter.ConfigFastest
+
+// Element is an element of the dump being transformed. The embedded element's Payload
+// is decoded from the input and does not reflect changes made through Set or Delete.
+type Element struct {
+	reader.Element
+
+	fields   object.Object
+	modified bool
+}
+
+// Get decodes the value of the given property into v. It returns false if the element
+// has no such property.
+func (e *Element) Get(key string, v interface{}) (bool, error) {
+	if err := e.parse(); err != nil {
+		return false, err
+	}
+
+	return e.fields.Unmarshal(key, v)
+}
+
+// Set replaces the value of the given property, adding the property if it is absent.
+func (e *Element) Set(key string, v interface{}) error {
+	if err := e.parse(); err != nil {
+		return err
+	}
+
+	value, err := marshaller.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	e.fields.Set(key, value)
+	e.modified = true
+	return nil
+}
+
+// Delete removes the given property.
+func (e *Element) Delete(key string) error {
+	if err := e.parse(); err != nil {
+		return err
+	}
+
+	e.fields.Delete(key)
+	e.modified = true
+	return nil
+}
+
+// parse decodes the properties of the raw element on first use.
+func (e *Element) parse() (err error) {
+	if e.fields == nil {
+		e.fields, err = object.Parse(e.Raw)
+	}
+
+	return err
+}
+
+// encode returns the JSON encoding of the (possibly modified) element.
+func (e *Element) encode() []byte {
+	if !e.modified {
+		return e.Raw
+	}
+
+	return e.fields.Marshal()
+}
+
+// Transformer modifies or removes elements of a dump.
+type Transformer interface {
+	// Transform modifies the given element in place. The element is removed from the
+	// output if false is returned.
+	Transform(e *Element) (bool, error)
+}
+
+// TransformerFunc is a function that implements the Transformer interface.
+type TransformerFunc func(e *Element) (bool, error)
+
+func (f TransformerFunc) Transform(e *Element) (bool, error) {
+	return f(e)
+}
+
+// Run reads the dump from the given reader, applies the given transformers in order to
+// each element, and writes the surviving elements to the given writer.
+//
+// Elements that refer to removed elements are removed automatically (and are not passed
+// to the transformers): edges whose outV, inV, or document was removed and $event vertices
+// whose data was removed. Removed elements are filtered from the inVs of an edge, and the
+// edge is removed if none remain. Symbols of a range-based documentSymbolResult that refer
+// to removed ranges are removed, and their children take their place. References are
+// resolved only to elements that precede the referencing element in the dump.
+//
+// The given writer is not flushed.
+func Run(ctx context.Context, r io.Reader, w writer.JSONWriter, transformers ...Transformer) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	removed := map[int]struct{}{}
+
+	for pair := range reader.Read(ctx, r, reader.WithRawLines()) {
+		if pair.Err != nil {
+			return pair.Err
+		}
+
+		e := &Element{Element: pair.Element}
+
+		keep, err := removeDanglingReferences(e, removed)
+		if err != nil {
+			return fmt.Errorf("line %d: %s", pair.Line, err)
+		}
+
+		for _, transformer := range transformers {
+			if !keep {
+				break
+			}
+
+			if keep, err = transformer.Transform(e); err != nil {
+				return fmt.Errorf("line %d: %s", pair.Line, err)
+			}
+		}
+
+		if !keep {
+			removed[e.ID] = struct{}{}
+			continue
+		}
+
+		w.Write(json.RawMessage(e.encode()))
+
+		if err := writer.Err(w); err != nil {
+			return err
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return writer.Err(w)
+}
+
+// removeDanglingReferences returns false if the given element refers to a removed element
+// and cannot be kept. Removed elements are filtered from the inVs of edges.
+func removeDanglingReferences(e *Element, removed map[int]struct{}) (bool, error) {
+	isRemoved := func(id int) bool {
+		_, ok := removed[id]
+		return ok
+	}
+
+	switch payload := e.Payload.(type) {
+	case reader.Edge:
+		if isRemoved(payload.OutV) || isRemoved(payload.InV) || isRemoved(payload.Document) {
+			return false, nil
+		}
+
+		numRemoved := 0
+		for _, id := range payload.InVs {
+			if isRemoved(id) {
+				numRemoved++
+			}
+		}
+		if numRemoved == 0 {
+			break
+		}
+		if numRemoved == len(payload.InVs) {
+			return false, nil
+		}
+
+		// Filter the original identifiers, which may differ from the interned identifiers
+		var original []json.RawMessage
+		if _, err := e.Get("inVs", &original); err != nil {
+			return false, err
+		}
+		if len(original) != len(payload.InVs) {
+			return false, fmt.Errorf("malformed inVs")
+		}
+
+		inVs := make([]json.RawMessage, 0, len(original)-numRemoved)
+		for i, id := range payload.InVs {
+			if !isRemoved(id) {
+				inVs = append(inVs, original[i])
+			}
+		}
+
+		if err := e.Set("inVs", inVs); err != nil {
+			return false, err
+		}
+
+	case reader.Event:
+		if isRemoved(payload.Data) {
+			return false, nil
+		}
+
+	case []reader.RangeBasedDocumentSymbol:
+		if !symbolsReferToRemoved(payload, isRemoved) {
+			break
+		}
+
+		var original []json.RawMessage
+		if _, err := e.Get("result", &original); err != nil {
+			return false, err
+		}
+
+		result, err := filterSymbols(payload, original, isRemoved)
+		if err != nil {
+			return false, err
+		}
+
+		if err := e.Set("result", result); err != nil {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+// symbolsReferToRemoved returns true if any of the given document symbols or their
+// descendants refers to a removed range.
+func symbolsReferToRemoved(symbols []reader.RangeBasedDocumentSymbol, isRemoved func(id int) bool) bool {
+	for _, symbol := range symbols {
+		if isRemoved(symbol.ID) || symbolsReferToRemoved(symbol.Children, isRemoved) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// filterSymbols returns the original encoding of the given document symbols without those
+// that refer to removed ranges. The children of a removed symbol take its place.
+func filterSymbols(symbols []reader.RangeBasedDocumentSymbol, original []json.RawMessage, isRemoved func(id int) bool) ([]json.RawMessage, error) {
+	if len(original) != len(symbols) {
+		return nil, fmt.Errorf("malformed documentSymbolResult")
+	}
+
+	filtered := make([]json.RawMessage, 0, len(symbols))
+	for i, symbol := range symbols {
+		o, err := object.Parse(original[i])
+		if err != nil {
+			return nil, err
+		}
+
+		var originalChildren []json.RawMessage
+		if _, err := o.Unmarshal("children", &originalChildren); err != nil {
+			return nil, err
+		}
+
+		children, err := filterSymbols(symbol.Children, originalChildren, isRemoved)
+		if err != nil {
+			return nil, err
+		}
+
+		if isRemoved(symbol.ID) {
+			filtered = append(filtered, children...)
+			continue
+		}
+
+		if len(children) == 0 {
+			o.Delete("children")
+		} else {
+			value, err := marshaller.Marshal(children)
+			if err != nil {
+				return nil, err
+			}
+			o.Set("children", value)
+		}
+
+		filtered = append(filtered, o.Marshal())
+	}
+
+	return filtered, nil
+}
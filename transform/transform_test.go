@@ -0,0 +1,110 @@
+package transform
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sourcegraph/lsif-protocol/internal/lsiftest"
+	"github.com/sourcegraph/lsif-protocol/writer"
+)
+
+func TestRun(t *testing.T) {
+	input := strings.Join([]string{
+		`{"id": 1, "type": "vertex", "label": "metaData", "version": "0.4.3", "projectRoot": "file:///build", "x-vendor": true}`,
+		`{"id": 2, "type": "vertex", "label": "document", "uri": "file:///build/foo.go", "languageId": "go"}`,
+		`{"id": 3, "type": "vertex", "label": "$event", "kind": "begin", "scope": "document", "data": 2}`,
+		`{"id": 4, "type": "vertex", "label": "range", "start": {"line": 1, "character": 5}, "end": {"line": 1, "character": 8}}`,
+		`{"id": 5, "type": "vertex", "label": "range", "start": {"line": 2, "character": 5}, "end": {"line": 2, "character": 8}}`,
+		`{"id": 6, "type": "edge", "label": "contains", "outV": 2, "inVs": [4, 5]}`,
+		`{"id": 7, "type": "vertex", "label": "hoverResult", "result": {"contents": [{"language": "go", "value": "func Foo()"}, "Foo does things."]}}`,
+		`{"id": 8, "type": "edge", "label": "textDocument/hover", "outV": 4, "inV": 7}`,
+		`{"id": 9, "type": "vertex", "label": "hoverResult", "result": {"contents": {"kind": "markdown", "value": "Just prose."}}}`,
+		`{"id": 10, "type": "edge", "label": "textDocument/hover", "outV": 5, "inV": 9}`,
+		`{"id": 11, "type": "vertex", "label": "diagnosticResult", "result": [{"range": {"start": {"line": 1, "character": 5}, "end": {"line": 1, "character": 8}}, "message": "oops"}]}`,
+		`{"id": 12, "type": "edge", "label": "textDocument/diagnostic", "outV": 2, "inV": 11}`,
+		`{"id": 13, "type": "vertex", "label": "definitionResult"}`,
+		`{"id": 14, "type": "edge", "label": "item", "outV": 13, "inVs": [4, 5], "document": 2}`,
+		`{"id": 15, "type": "vertex", "label": "$event", "kind": "end", "scope": "document", "data": 2}`,
+	}, "\n")
+
+	dropSecondRange := TransformerFunc(func(e *Element) (bool, error) {
+		return e.ID != 5, nil
+	})
+	rewriteURIs := RewriteDocumentURIs(func(uri string) string {
+		return strings.Replace(uri, "file:///build/", "file:///src/", 1)
+	})
+
+	output := lsiftest.Lines(t, func(w writer.JSONWriter) error {
+		return Run(context.Background(), strings.NewReader(input), w, dropSecondRange, rewriteURIs, StripHoverDocumentation(), RemoveDiagnostics())
+	})
+
+	expected := []string{
+		`{"id": 1, "type": "vertex", "label": "metaData", "version": "0.4.3", "projectRoot": "file:///build", "x-vendor": true}`,
+		`{"id":2,"type":"vertex","label":"document","uri":"file:///src/foo.go","languageId":"go"}`,
+		`{"id": 3, "type": "vertex", "label": "$event", "kind": "begin", "scope": "document", "data": 2}`,
+		`{"id": 4, "type": "vertex", "label": "range", "start": {"line": 1, "character": 5}, "end": {"line": 1, "character": 8}}`,
+		`{"id":6,"type":"edge","label":"contains","outV":2,"inVs":[4]}`,
+		`{"id":7,"type":"vertex","label":"hoverResult","result":{"contents":[{"language": "go", "value": "func Foo()"}]}}`,
+		`{"id": 8, "type": "edge", "label": "textDocument/hover", "outV": 4, "inV": 7}`,
+		`{"id": 13, "type": "vertex", "label": "definitionResult"}`,
+		`{"id":14,"type":"edge","label":"item","outV":13,"inVs":[4],"document":2}`,
+		`{"id": 15, "type": "vertex", "label": "$event", "kind": "end", "scope": "document", "data": 2}`,
+	}
+	if diff := cmp.Diff(expected, output); diff != "" {
+		t.Errorf("unexpected output (-want +got):\n%s", diff)
+	}
+}
+
+func TestRunRemovesEventsOfRemovedDocuments(t *testing.T) {
+	input := strings.Join([]string{
+		`{"id": 1, "type": "vertex", "label": "document", "uri": "file:///foo.go"}`,
+		`{"id": 2, "type": "vertex", "label": "$event", "kind": "begin", "scope": "document", "data": 1}`,
+		`{"id": 3, "type": "vertex", "label": "$event", "kind": "end", "scope": "document", "data": 1}`,
+	}, "\n")
+
+	output := lsiftest.Lines(t, func(w writer.JSONWriter) error {
+		return Run(context.Background(), strings.NewReader(input), w, DropLabels("document"))
+	})
+
+	if len(output) != 0 {
+		t.Errorf("unexpected output: %v", output)
+	}
+}
+
+func TestCodeBlocks(t *testing.T) {
+	markdown := "```go\nfunc Foo()\n```\n\nFoo does things.\n\n```go\ntype Bar struct{}\n```"
+
+	expected := "```go\nfunc Foo()\n```\n```go\ntype Bar struct{}\n```"
+	if diff := cmp.Diff(expected, codeBlocks(markdown)); diff != "" {
+		t.Errorf("unexpected code blocks (-want +got):\n%s", diff)
+	}
+}
+
+func TestRunFiltersRangeBasedDocumentSymbols(t *testing.T) {
+	input := strings.Join([]string{
+		`{"id": 1, "type": "vertex", "label": "range", "start": {"line": 1, "character": 0}, "end": {"line": 9, "character": 1}}`,
+		`{"id": 2, "type": "vertex", "label": "range", "start": {"line": 2, "character": 1}, "end": {"line": 2, "character": 4}}`,
+		`{"id": 3, "type": "vertex", "label": "range", "start": {"line": 3, "character": 1}, "end": {"line": 3, "character": 4}}`,
+		`{"id": 4, "type": "vertex", "label": "documentSymbolResult", "result": [{"id": 1, "children": [{"id": 2}, {"id": 3}]}]}`,
+		`{"id": 5, "type": "vertex", "label": "documentSymbolResult", "result": [{"id": 2, "children": [{"id": 3}]}]}`,
+	}, "\n")
+
+	dropRanges := TransformerFunc(func(e *Element) (bool, error) {
+		return e.ID != 1 && e.ID != 3, nil
+	})
+
+	output := lsiftest.Lines(t, func(w writer.JSONWriter) error {
+		return Run(context.Background(), strings.NewReader(input), w, dropRanges)
+	})
+
+	expected := []string{
+		`{"id": 2, "type": "vertex", "label": "range", "start": {"line": 2, "character": 1}, "end": {"line": 2, "character": 4}}`,
+		`{"id":4,"type":"vertex","label":"documentSymbolResult","result":[{"id":2}]}`,
+		`{"id":5,"type":"vertex","label":"documentSymbolResult","result":[{"id":2}]}`,
+	}
+	if diff := cmp.Diff(expected, output); diff != "" {
+		t.Errorf("unexpected output (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,175 @@
+package transform
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"github.com/sourcegraph/lsif-protocol/internal/object"
+)
+
+// DropLabels removes the vertices and edges with any of the given labels.
+func DropLabels(labels ...string) Transformer {
+	set := map[string]struct{}{}
+	for _, label := range labels {
+		set[label] = struct{}{}
+	}
+
+	return TransformerFunc(func(e *Element) (bool, error) {
+		_, ok := set[e.Label]
+		return !ok, nil
+	})
+}
+
+// RemoveDiagnostics removes diagnosticResult vertices along with the textDocument/diagnostic
+// edges that refer to them.
+func RemoveDiagnostics() Transformer {
+	return DropLabels("diagnosticResult", "textDocument/diagnostic")
+}
+
+// RewriteDocumentURIs replaces the URI of each document vertex with the value returned by
+// the given function.
+func RewriteDocumentURIs(rewrite func(uri string) string) Transformer {
+	return TransformerFunc(func(e *Element) (bool, error) {
+		if e.Type != "vertex" || e.Label != "document" {
+			return true, nil
+		}
+
+		var uri string
+		if _, err := e.Get("uri", &uri); err != nil {
+			return false, err
+		}
+
+		if rewritten := rewrite(uri); rewritten != uri {
+			if err := e.Set("uri", rewritten); err != nil {
+				return false, err
+			}
+		}
+
+		return true, nil
+	})
+}
+
+// StripHoverDocumentation removes documentation from hover results while retaining code
+// (such as signatures). Marked strings tagged with a language are kept as-is, and markdown
+// content is reduced to its fenced code blocks. Hover results left without content are
+// removed along with the textDocument/hover edges that refer to them.
+func StripHoverDocumentation() Transformer {
+	return TransformerFunc(func(e *Element) (bool, error) {
+		if e.Type != "vertex" || e.Label != "hoverResult" {
+			return true, nil
+		}
+
+		var raw json.RawMessage
+		if _, err := e.Get("result", &raw); err != nil {
+			return false, err
+		}
+
+		result, err := object.Parse(raw)
+		if err != nil {
+			return false, err
+		}
+
+		contents, _ := result.Get("contents")
+		contents, ok, err := stripContents(contents)
+		if err != nil || !ok {
+			return false, err
+		}
+		result.Set("contents", contents)
+
+		if err := e.Set("result", json.RawMessage(result.Marshal())); err != nil {
+			return false, err
+		}
+
+		return true, nil
+	})
+}
+
+// stripContents removes documentation from the given hover contents, which may be a
+// MarkedString, a MarkupContent, or a list of MarkedStrings. It returns false if no
+// content remains.
+func stripContents(contents []byte) ([]byte, bool, error) {
+	contents = bytes.TrimSpace(contents)
+	if len(contents) == 0 {
+		return nil, false, nil
+	}
+
+	switch contents[0] {
+	case '"':
+		var value string
+		if err := marshaller.Unmarshal(contents, &value); err != nil {
+			return nil, false, err
+		}
+
+		code := codeBlocks(value)
+		stripped, err := marshaller.Marshal(code)
+		return stripped, code != "", err
+
+	case '{':
+		o, err := object.Parse(contents)
+		if err != nil {
+			return nil, false, err
+		}
+		if _, ok := o.Get("language"); ok {
+			return contents, true, nil
+		}
+
+		var code string
+		if o.GetString("kind") == "markdown" {
+			code = codeBlocks(o.GetString("value"))
+		}
+
+		value, err := marshaller.Marshal(code)
+		if err != nil {
+			return nil, false, err
+		}
+		o.Set("value", value)
+		return o.Marshal(), code != "", nil
+
+	case '[':
+		var items []json.RawMessage
+		if err := marshaller.Unmarshal(contents, &items); err != nil {
+			return nil, false, err
+		}
+
+		stripped := make([]json.RawMessage, 0, len(items))
+		for _, item := range items {
+			item, ok, err := stripContents(item)
+			if err != nil {
+				return nil, false, err
+			}
+			if ok {
+				stripped = append(stripped, item)
+			}
+		}
+
+		value, err := marshaller.Marshal(stripped)
+		return value, len(stripped) > 0, err
+	}
+
+	return nil, false, nil
+}
+
+// codeBlocks returns the fenced code blocks of the given markdown text.
+func codeBlocks(markdown string) string {
+	var blocks []string
+	var block []string
+	inBlock := false
+
+	for _, line := range strings.Split(markdown, "\n") {
+		isFence := strings.HasPrefix(strings.TrimSpace(line), "```")
+		if inBlock || isFence {
+			block = append(block, line)
+		}
+
+		if isFence {
+			if inBlock {
+				blocks = append(blocks, strings.Join(block, "\n"))
+				block = nil
+			}
+			inBlock = !inBlock
+		}
+	}
+
+	return strings.Join(blocks, "\n")
+}
@@ -0,0 +1,110 @@
+package protocol
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// vertexTypes maps vertex labels to constructors of the struct decoded for vertices with that label.
+var vertexTypes = map[VertexLabel]func() interface{}{
+	VertexMetaData:             func() interface{} { return &MetaData{} },
+	VertexProject:              func() interface{} { return &Project{} },
+	VertexRange:                func() interface{} { return &Range{} },
+	VertexLocation:             func() interface{} { return &Location{} },
+	VertexDocument:             func() interface{} { return &Document{} },
+	VertexMoniker:              func() interface{} { return &Moniker{} },
+	VertexPackageInformation:   func() interface{} { return &PackageInformation{} },
+	VertexResultSet:            func() interface{} { return &ResultSet{} },
+	VertexFoldingRangeResult:   func() interface{} { return &FoldingRangeResult{} },
+	VertexDocumentLinkResult:   func() interface{} { return &DocumentLinkResult{} },
+	VertexDianosticResult:      func() interface{} { return &DiagnosticResult{} },
+	VertexDeclarationResult:    func() interface{} { return &DeclarationResult{} },
+	VertexDefinitionResult:     func() interface{} { return &DefinitionResult{} },
+	VertexTypeDefinitionResult: func() interface{} { return &TypeDefinitionResult{} },
+	VertexHoverResult:          func() interface{} { return &HoverResult{} },
+	VertexReferenceResult:      func() interface{} { return &ReferenceResult{} },
+	VertexImplementationResult: func() interface{} { return &ImplementationResult{} },
+	VertexEvent:                func() interface{} { return &Event{} },
+}
+
+// edgeTypes maps edge labels to constructors of the struct decoded for edges with that label.
+var edgeTypes = map[EdgeLabel]func() interface{}{
+	EdgeContains:                   func() interface{} { return &Contains{} },
+	EdgeItem:                       func() interface{} { return &Item{} },
+	EdgeNext:                       func() interface{} { return &Next{} },
+	EdgeMoniker:                    func() interface{} { return &MonikerEdge{} },
+	EdgeNextMoniker:                func() interface{} { return &NextMonikerEdge{} },
+	EdgePackageInformation:         func() interface{} { return &PackageInformationEdge{} },
+	EdgeTextDocumentDocumentSymbol: func() interface{} { return &TextDocumentDocumentSymbol{} },
+	EdgeTextDocumentFoldingRange:   func() interface{} { return &TextDocumentFoldingRange{} },
+	EdgeTextDocumentDocumentLink:   func() interface{} { return &TextDocumentDocumentLink{} },
+	EdgeTextDocumentDiagnostic:     func() interface{} { return &TextDocumentDiagnostic{} },
+	EdgeTextDocumentDefinition:     func() interface{} { return &TextDocumentDefinition{} },
+	EdgeTextDocumentDeclaration:    func() interface{} { return &TextDocumentDeclaration{} },
+	EdgeTextDocumentTypeDefinition: func() interface{} { return &TextDocumentTypeDefinition{} },
+	EdgeTextDocumentHover:          func() interface{} { return &TextDocumentHover{} },
+	EdgeTextDocumentReferences:     func() interface{} { return &TextDocumentReferences{} },
+	EdgeTextDocumentImplementation: func() interface{} { return &TextDocumentImplementation{} },
+}
+
+// Unmarshal decodes a single line of a dump into the struct produced by the constructor
+// for the element's label (e.g., Range for a range vertex and Item for an item edge). The
+// returned value is a struct, not a pointer, so that it can be passed back to a JSONWriter
+// and re-emitted unchanged. Identifiers must be numeric.
+//
+// A documentSymbolResult vertex is decoded as a RangeBasedDocumentSymbolResult if its
+// symbols refer to range vertices and as a DocumentSymbolResult otherwise.
+func Unmarshal(line []byte) (interface{}, error) {
+	var element struct {
+		Type  ElementType `json:"type"`
+		Label string      `json:"label"`
+	}
+	if err := marshaller.Unmarshal(line, &element); err != nil {
+		return nil, err
+	}
+
+	var newElement func() interface{}
+	switch element.Type {
+	case ElementVertex:
+		if VertexLabel(element.Label) == VertexDocumentSymbolResult {
+			return unmarshalDocumentSymbolResult(line)
+		}
+
+		newElement = vertexTypes[VertexLabel(element.Label)]
+	case ElementEdge:
+		newElement = edgeTypes[EdgeLabel(element.Label)]
+	default:
+		return nil, fmt.Errorf("unknown element type %q", element.Type)
+	}
+
+	if newElement == nil {
+		return nil, fmt.Errorf("unknown %s label %q", element.Type, element.Label)
+	}
+
+	return unmarshalInto(line, newElement())
+}
+
+func unmarshalDocumentSymbolResult(line []byte) (interface{}, error) {
+	var payload struct {
+		Result []struct {
+			ID *uint64 `json:"id"`
+		} `json:"result"`
+	}
+	if err := marshaller.Unmarshal(line, &payload); err != nil {
+		return nil, err
+	}
+
+	if len(payload.Result) > 0 && payload.Result[0].ID != nil {
+		return unmarshalInto(line, &RangeBasedDocumentSymbolResult{})
+	}
+	return unmarshalInto(line, &DocumentSymbolResult{})
+}
+
+// unmarshalInto decodes the given line into the given pointer and returns the value it points to.
+func unmarshalInto(line []byte, ptr interface{}) (interface{}, error) {
+	if err := marshaller.Unmarshal(line, ptr); err != nil {
+		return nil, err
+	}
+
+	return reflect.ValueOf(ptr).Elem().Interface(), nil
+}
@@ -0,0 +1,111 @@
+package protocol
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestUnmarshalRoundTrip(t *testing.T) {
+	pos := func(line, character int) Pos { return Pos{Line: line, Character: character} }
+	rangeData := RangeData{Start: pos(1, 2), End: pos(1, 5)}
+
+	elements := []interface{}{
+		NewMetaData(1, "file:///build", ToolInfo{Name: "lsif-test", Args: []string{"-v"}}),
+		NewProject(2, "go"),
+		NewDocument(3, "go", "file:///build/foo.go"),
+		NewRange(4, pos(1, 2), pos(1, 5)),
+		NewRangeWithTag(5, pos(3, 0), pos(3, 3), RangeTag{Type: RangeTagDefinition, Text: "Foo", Kind: SymbolKindFunction, FullRange: &rangeData}),
+		NewLocation(6, pos(1, 2), pos(1, 5)),
+		NewResultSet(7),
+		NewHoverResult(8, []MarkedString{NewMarkedString("func Foo()", "go"), RawMarkedString("Foo does things.")}),
+		NewMoniker(9, "export", "gomod", "foo:Foo"),
+		NewPackageInformation(10, "foo", "gomod", "v1.0.0"),
+		NewDefinitionResult(11),
+		NewDeclarationResult(12),
+		NewTypeDefinitionResult(13),
+		ReferenceResult{Vertex: NewReferenceResult(14).Vertex},
+		NewImplementationResult(15),
+		NewDocumentSymbolResult(16, []DocumentSymbol{{Name: "Foo", Kind: SymbolKindFunction, Range: rangeData, SelectionRange: rangeData}}),
+		NewRangeBasedDocumentSymbolResult(17, []RangeBasedDocumentSymbol{{ID: 5, Children: []RangeBasedDocumentSymbol{{ID: 4}}}}),
+		NewFoldingRangeResult(18, []FoldingRange{{StartLine: 1, EndLine: 3, Kind: FoldingRangeKindImports}}),
+		NewDocumentLinkResult(19, []DocumentLink{{Range: rangeData, Target: "https://example.com"}}),
+		NewDiagnosticResult(20, []Diagnostic{
+			{Range: rangeData, Severity: DiagnosticSeverityError, Message: "oops"},
+			{Range: rangeData, Code: NewNumericDiagnosticCode(2304), Message: "cannot find name"},
+			{Range: rangeData, Code: NewDiagnosticCode("SA4006"), Message: "unused value"},
+		}),
+		NewEvent(21, EventKindBegin, EventScopeDocument, 3),
+		NewContains(22, 3, []uint64{4, 5}),
+		NewItemOfDefinitions(23, 11, []uint64{5}, 3),
+		NewNext(24, 5, 7),
+		NewMonikerEdge(25, 7, 9),
+		NewNextMonikerEdge(26, 9, 9),
+		NewPackageInformationEdge(27, 9, 10),
+		NewTextDocumentHover(28, 7, 8),
+		NewTextDocumentDefinition(29, 7, 11),
+		NewTextDocumentDeclaration(30, 7, 12),
+		NewTextDocumentTypeDefinition(31, 7, 13),
+		NewTextDocumentReferences(32, 7, 14),
+		NewTextDocumentImplementation(33, 7, 15),
+		NewTextDocumentDocumentSymbol(34, 3, 16),
+		NewTextDocumentFoldingRange(35, 3, 18),
+		NewTextDocumentDocumentLink(36, 3, 19),
+		NewTextDocumentDiagnostic(37, 3, 20),
+	}
+
+	for _, element := range elements {
+		data, err := marshaller.Marshal(element)
+		if err != nil {
+			t.Fatalf("unexpected error marshalling element: %s", err)
+		}
+
+		decoded, err := Unmarshal(data)
+		if err != nil {
+			t.Fatalf("unexpected error unmarshalling %s: %s", data, err)
+		}
+
+		if diff := cmp.Diff(element, decoded, cmp.AllowUnexported(MarkedString{})); diff != "" {
+			t.Errorf("unexpected element for %s (-want +got):\n%s", data, diff)
+		}
+
+		redata, err := marshaller.Marshal(decoded)
+		if err != nil {
+			t.Fatalf("unexpected error marshalling element: %s", err)
+		}
+		if diff := cmp.Diff(string(data), string(redata)); diff != "" {
+			t.Errorf("unexpected output (-want +got):\n%s", diff)
+		}
+	}
+}
+
+func TestUnmarshalNumericDiagnosticCode(t *testing.T) {
+	line := `{"id":1,"type":"vertex","label":"diagnosticResult","result":[{"range":{"start":{"line":1,"character":2},"end":{"line":1,"character":5}},"code":2304,"message":"cannot find name"}]}`
+
+	element, err := Unmarshal([]byte(line))
+	if err != nil {
+		t.Fatalf("unexpected error unmarshalling diagnostic result: %s", err)
+	}
+
+	result, ok := element.(DiagnosticResult)
+	if !ok {
+		t.Fatalf("unexpected element type %T", element)
+	}
+	if diff := cmp.Diff(NewNumericDiagnosticCode(2304), result.Result[0].Code); diff != "" {
+		t.Errorf("unexpected code (-want +got):\n%s", diff)
+	}
+
+	data, err := marshaller.Marshal(element)
+	if err != nil {
+		t.Fatalf("unexpected error marshalling element: %s", err)
+	}
+	if diff := cmp.Diff(line, string(data)); diff != "" {
+		t.Errorf("unexpected output (-want +got):\n%s", diff)
+	}
+}
+
+func TestUnmarshalUnknownLabel(t *testing.T) {
+	if _, err := Unmarshal([]byte(`{"id": 1, "type": "vertex", "label": "unknown"}`)); err == nil {
+		t.Errorf("expected error unmarshalling unknown vertex label")
+	}
+}
@@ -0,0 +1,364 @@
+// Package validation checks an LSIF dump against the graph invariants required by the
+// LSIF specification.
+package validation
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"unicode/utf8"
+
+	"github.com/sourcegraph/lsif-protocol/reader"
+)
+
+// Error describes a violation of an LSIF graph invariant.
+type Error struct {
+	// Message describes the violated invariant.
+	Message string
+
+	// ElementID is the (interned) identifier of the offending element.
+	ElementID int
+
+	// Line is the 1-indexed line of the input containing the offending element. Elements
+	// that are reported only after the entire input has been read (such as ranges that
+	// are not contained by any document) carry the line on which they were defined.
+	Line int
+}
+
+func (e Error) Error() string {
+	return fmt.Sprintf("line %d: element %d: %s", e.Line, e.ElementID, e.Message)
+}
+
+// Option configures a validation run.
+type Option func(*validator)
+
+// WithSourceReader enables checking that ranges lie within the bounds of the source
+// text of their documents. The given function is invoked with the path of each document
+// relative to the dump's project root and should return the document's content. Documents
+// outside of the project root are not checked.
+func WithSourceReader(readSource func(path string) ([]byte, error)) Option {
+	return func(v *validator) {
+		v.readSource = readSource
+	}
+}
+
+// Validate reads the given LSIF dump and returns the invariant violations found within it,
+// ordered by line. A non-nil error is returned only if the input could not be read.
+func Validate(ctx context.Context, r io.Reader, options ...Option) ([]Error, error) {
+	v := newValidator()
+	for _, option := range options {
+		option(v)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	for pair := range reader.Read(ctx, r) {
+		if pair.Err != nil {
+			return nil, pair.Err
+		}
+
+		v.add(pair.Element, pair.Line)
+	}
+
+	return v.finish(), nil
+}
+
+// elementInfo tracks the type, label, and position of an element.
+type elementInfo struct {
+	typ   string
+	label string
+	line  int
+}
+
+// reference is an edge's reference to a vertex that had not been seen when the edge was read.
+type reference struct {
+	edgeID   int
+	line     int
+	property string
+	id       int
+	label    string
+}
+
+type validator struct {
+	readSource  func(path string) ([]byte, error)
+	count       int
+	projectRoot string
+	elements    map[int]elementInfo
+	documents   map[int]string
+	ranges      map[int]reader.Range
+	containers  map[int]map[int]struct{}
+	forwardRefs []reference
+	errors      []Error
+}
+
+func newValidator() *validator {
+	return &validator{
+		elements:   map[int]elementInfo{},
+		documents:  map[int]string{},
+		ranges:     map[int]reader.Range{},
+		containers: map[int]map[int]struct{}{},
+	}
+}
+
+func (v *validator) add(element reader.Element, line int) {
+	first := v.count == 0
+	v.count++
+
+	if first && !(element.Type == "vertex" && element.Label == "metaData") {
+		v.errorf(element.ID, line, "first element must be a metaData vertex, found %s %q", element.Type, element.Label)
+	}
+	if !first && element.Type == "vertex" && element.Label == "metaData" {
+		v.errorf(element.ID, line, "metaData vertex must be the first element")
+	}
+
+	if info, ok := v.elements[element.ID]; ok {
+		v.errorf(element.ID, line, "duplicate element identifier (first defined on line %d)", info.line)
+		return
+	}
+	v.elements[element.ID] = elementInfo{typ: element.Type, label: element.Label, line: line}
+
+	switch element.Type {
+	case "vertex":
+		v.addVertex(element, line)
+
+	case "edge":
+		edge, ok := element.Payload.(reader.Edge)
+		if !ok {
+			v.errorf(element.ID, line, "malformed edge")
+			return
+		}
+
+		v.addEdge(element, edge, line)
+
+	default:
+		v.errorf(element.ID, line, "unknown element type %q", element.Type)
+	}
+}
+
+func (v *validator) addVertex(element reader.Element, line int) {
+	switch payload := element.Payload.(type) {
+	case reader.MetaData:
+		v.projectRoot = payload.ProjectRoot
+
+	case reader.Document:
+		v.documents[element.ID] = payload.URI
+
+	case reader.Range:
+		v.ranges[element.ID] = payload
+
+		if payload.StartLine < 0 || payload.StartCharacter < 0 || payload.EndLine < 0 || payload.EndCharacter < 0 {
+			v.errorf(element.ID, line, "range has a negative position")
+		} else if reader.ComparePositions(payload.StartLine, payload.StartCharacter, payload.EndLine, payload.EndCharacter) > 0 {
+			v.errorf(element.ID, line, "range ends before it starts")
+		}
+	}
+}
+
+func (v *validator) addEdge(element reader.Element, edge reader.Edge, line int) {
+	// An invalid outV is reported here, but the remainder of the edge is still checked
+	v.checkVertex(element.ID, line, "outV", edge.OutV, "")
+
+	inVs := edge.InVs
+	if edge.InV != 0 {
+		inVs = append([]int{edge.InV}, inVs...)
+	}
+	if len(inVs) == 0 {
+		v.errorf(element.ID, line, "edge has no inV or inVs")
+	}
+
+	for _, inV := range inVs {
+		v.checkVertex(element.ID, line, "inV", inV, "")
+	}
+
+	switch element.Label {
+	case "contains":
+		// Containment by a vertex that has not yet been seen is resolved once the entire input
+		// has been read, so that a bad outV is not also reported for each of its inVs
+		if info, ok := v.elements[edge.OutV]; edge.OutV != 0 && (!ok || info.label == "document") {
+			for _, inV := range inVs {
+				if _, ok := v.containers[inV]; !ok {
+					v.containers[inV] = map[int]struct{}{}
+				}
+				v.containers[inV][edge.OutV] = struct{}{}
+			}
+		}
+
+	case "item":
+		v.checkVertex(element.ID, line, "document", edge.Document, "document")
+	}
+}
+
+// checkVertex ensures that the given identifier refers to a previously seen vertex with the
+// given label (if non-empty). References to identifiers that have not yet been seen are
+// re-checked once the entire input has been read.
+func (v *validator) checkVertex(edgeID, line int, property string, id int, label string) (elementInfo, bool) {
+	if id == 0 {
+		v.errorf(edgeID, line, "edge has no %s", property)
+		return elementInfo{}, false
+	}
+
+	info, ok := v.elements[id]
+	if !ok {
+		v.forwardRefs = append(v.forwardRefs, reference{edgeID: edgeID, line: line, property: property, id: id, label: label})
+		return elementInfo{}, false
+	}
+
+	return info, v.checkReference(reference{edgeID: edgeID, line: line, property: property, id: id, label: label}, info)
+}
+
+// checkReference ensures that the referenced element is a vertex with the expected label.
+func (v *validator) checkReference(ref reference, info elementInfo) bool {
+	if info.typ != "vertex" {
+		v.errorf(ref.edgeID, ref.line, "%s %d refers to an edge", ref.property, ref.id)
+		return false
+	}
+
+	if ref.label != "" && info.label != ref.label {
+		v.errorf(ref.edgeID, ref.line, "%s %d refers to a %s vertex, expected %s", ref.property, ref.id, info.label, ref.label)
+		return false
+	}
+
+	return true
+}
+
+// documentContainers returns the identifiers of the documents that contain the given range.
+// It also returns true if the range is contained by an outV that does not refer to any
+// vertex, which has already been reported as a dangling reference.
+func (v *validator) documentContainers(rangeID int) (documentIDs []int, unresolved bool) {
+	for id := range v.containers[rangeID] {
+		info, ok := v.elements[id]
+		if !ok {
+			unresolved = true
+		} else if info.typ == "vertex" && info.label == "document" {
+			documentIDs = append(documentIDs, id)
+		}
+	}
+
+	return documentIDs, unresolved
+}
+
+func (v *validator) finish() []Error {
+	for _, ref := range v.forwardRefs {
+		info, ok := v.elements[ref.id]
+		if !ok {
+			v.errorf(ref.edgeID, ref.line, "dangling reference: %s %d does not refer to any vertex", ref.property, ref.id)
+			continue
+		}
+
+		if v.checkReference(ref, info) {
+			v.errorf(ref.edgeID, ref.line, "out-of-order reference: %s %d refers to a vertex defined later on line %d", ref.property, ref.id, info.line)
+		}
+	}
+
+	sources := map[int][][]byte{}
+
+	for id, r := range v.ranges {
+		line := v.elements[id].line
+
+		documentIDs, unresolved := v.documentContainers(id)
+
+		switch n := len(documentIDs); n {
+		case 0:
+			if !unresolved {
+				v.errorf(id, line, "range is not contained by any document")
+			}
+
+		case 1:
+			for _, documentID := range documentIDs {
+				if lines, ok := v.source(documentID, sources); ok {
+					v.checkBounds(id, line, r, lines)
+				}
+			}
+
+		default:
+			v.errorf(id, line, "range is contained by %d documents", n)
+		}
+	}
+
+	sort.SliceStable(v.errors, func(i, j int) bool {
+		if v.errors[i].Line != v.errors[j].Line {
+			return v.errors[i].Line < v.errors[j].Line
+		}
+
+		return v.errors[i].ElementID < v.errors[j].ElementID
+	})
+
+	return v.errors
+}
+
+// source returns the lines of the source text of the given document, if available.
+// The result is memoized in the given map.
+func (v *validator) source(documentID int, sources map[int][][]byte) ([][]byte, bool) {
+	if v.readSource == nil {
+		return nil, false
+	}
+
+	if lines, ok := sources[documentID]; ok {
+		return lines, lines != nil
+	}
+	sources[documentID] = nil
+
+	uri := v.documents[documentID]
+	path, ok := reader.RelativePath(v.projectRoot, uri)
+	if !ok {
+		return nil, false
+	}
+
+	content, err := v.readSource(path)
+	if err != nil {
+		v.errorf(documentID, v.elements[documentID].line, "cannot read source of %s: %s", uri, err)
+		return nil, false
+	}
+
+	lines := bytes.Split(content, []byte("\n"))
+	for i, line := range lines {
+		lines[i] = bytes.TrimSuffix(line, []byte("\r"))
+	}
+
+	sources[documentID] = lines
+	return lines, true
+}
+
+// checkBounds ensures that the given range lies within the given source text. Characters
+// are measured in UTF-16 code units as required by the LSIF position encoding.
+func (v *validator) checkBounds(id, line int, r reader.Range, lines [][]byte) {
+	for _, pos := range [][2]int{{r.StartLine, r.StartCharacter}, {r.EndLine, r.EndCharacter}} {
+		if pos[0] >= len(lines) {
+			v.errorf(id, line, "range position %d:%d is outside of the document, which has %d lines", pos[0], pos[1], len(lines))
+			return
+		}
+
+		if n := utf16Length(lines[pos[0]]); pos[1] > n {
+			v.errorf(id, line, "range position %d:%d is outside of the document, whose line %d has %d characters", pos[0], pos[1], pos[0], n)
+			return
+		}
+	}
+}
+
+func (v *validator) errorf(id, line int, format string, args ...interface{}) {
+	v.errors = append(v.errors, Error{
+		Message:   fmt.Sprintf(format, args...),
+		ElementID: id,
+		Line:      line,
+	})
+}
+
+// utf16Length returns the number of UTF-16 code units required to encode the given text.
+func utf16Length(text []byte) int {
+	n := 0
+	for len(text) > 0 {
+		r, size := utf8.DecodeRune(text)
+		text = text[size:]
+
+		if r >= 0x10000 {
+			n += 2
+		} else {
+			n++
+		}
+	}
+
+	return n
+}
@@ -0,0 +1,181 @@
+package validation
+
+import (
+	"context"
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sourcegraph/lsif-protocol/reader"
+)
+
+func TestValidate(t *testing.T) {
+	input := strings.Join([]string{
+		`{"id": 1, "type": "vertex", "label": "metaData", "version": "0.4.3", "projectRoot": "file:///test"}`,
+		`{"id": 2, "type": "vertex", "label": "document", "uri": "file:///test/foo.go"}`,
+		`{"id": 3, "type": "vertex", "label": "range", "start": {"line": 1, "character": 2}, "end": {"line": 1, "character": 5}}`,
+		`{"id": 4, "type": "edge", "label": "contains", "outV": 2, "inVs": [3]}`,
+		`{"id": 5, "type": "vertex", "label": "definitionResult"}`,
+		`{"id": 6, "type": "edge", "label": "textDocument/definition", "outV": 3, "inV": 5}`,
+		`{"id": 7, "type": "edge", "label": "item", "outV": 5, "inVs": [3], "document": 2}`,
+	}, "\n")
+
+	errs, err := Validate(context.Background(), strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("unexpected error validating dump: %s", err)
+	}
+	if len(errs) != 0 {
+		t.Errorf("unexpected validation errors: %v", errs)
+	}
+}
+
+func TestValidateErrors(t *testing.T) {
+	input := strings.Join([]string{
+		`{"id": 2, "type": "vertex", "label": "document", "uri": "file:///test/foo.go"}`,
+		`{"id": 1, "type": "vertex", "label": "metaData", "version": "0.4.3", "projectRoot": "file:///test"}`,
+		`{"id": 3, "type": "vertex", "label": "range", "start": {"line": 1, "character": 2}, "end": {"line": 1, "character": 5}}`,
+		`{"id": 4, "type": "vertex", "label": "range", "start": {"line": 2, "character": 2}, "end": {"line": 2, "character": 5}}`,
+		`{"id": 5, "type": "vertex", "label": "document", "uri": "file:///test/bar.go"}`,
+		`{"id": 6, "type": "edge", "label": "contains", "outV": 2, "inVs": [3]}`,
+		`{"id": 7, "type": "edge", "label": "contains", "outV": 5, "inVs": [3]}`,
+		`{"id": 7, "type": "vertex", "label": "definitionResult"}`,
+		`{"id": 8, "type": "vertex", "label": "definitionResult"}`,
+		`{"id": 9, "type": "edge", "label": "textDocument/definition", "outV": 3, "inV": 42}`,
+		`{"id": 10, "type": "edge", "label": "item", "outV": 8, "inVs": [3], "document": 4}`,
+	}, "\n")
+
+	errs, err := Validate(context.Background(), strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("unexpected error validating dump: %s", err)
+	}
+
+	expectedErrors := []Error{
+		{Line: 1, ElementID: 2, Message: `first element must be a metaData vertex, found vertex "document"`},
+		{Line: 2, ElementID: 1, Message: "metaData vertex must be the first element"},
+		{Line: 3, ElementID: 3, Message: "range is contained by 2 documents"},
+		{Line: 4, ElementID: 4, Message: "range is not contained by any document"},
+		{Line: 8, ElementID: 7, Message: "duplicate element identifier (first defined on line 7)"},
+		{Line: 10, ElementID: 9, Message: "dangling reference: inV 42 does not refer to any vertex"},
+		{Line: 11, ElementID: 10, Message: "document 4 refers to a range vertex, expected document"},
+	}
+	if diff := cmp.Diff(expectedErrors, errs); diff != "" {
+		t.Errorf("unexpected validation errors (-want +got):\n%s", diff)
+	}
+}
+
+func TestValidateMalformedLineReleasesReader(t *testing.T) {
+	lines := []string{`{"id": 1, "type": "vertex", "label": "metaData"`}
+	for i := 0; i < reader.ChannelBufferSize*4; i++ {
+		lines = append(lines, `{"id": 2, "type": "vertex", "label": "resultSet"}`)
+	}
+	input := strings.Join(lines, "\n")
+
+	before := runtime.NumGoroutine()
+	for i := 0; i < 10; i++ {
+		if _, err := Validate(context.Background(), strings.NewReader(input)); err == nil {
+			t.Fatalf("expected error validating malformed dump")
+		}
+	}
+
+	deadline := time.Now().Add(time.Second * 10)
+	for runtime.NumGoroutine() > before {
+		if time.Now().After(deadline) {
+			t.Fatalf("reader goroutines were not released. before=%d after=%d", before, runtime.NumGoroutine())
+		}
+
+		time.Sleep(time.Millisecond * 10)
+	}
+}
+
+func TestValidateOutOfOrder(t *testing.T) {
+	input := strings.Join([]string{
+		`{"id": 1, "type": "vertex", "label": "metaData", "version": "0.4.3", "projectRoot": "file:///test"}`,
+		`{"id": 2, "type": "vertex", "label": "document", "uri": "file:///test/foo.go"}`,
+		`{"id": 4, "type": "edge", "label": "contains", "outV": 2, "inVs": [3]}`,
+		`{"id": 3, "type": "vertex", "label": "range", "start": {"line": 2, "character": 1}, "end": {"line": 1, "character": 5}}`,
+		`{"id": 5, "type": "edge", "label": "next", "outV": 3, "inV": 4}`,
+	}, "\n")
+
+	errs, err := Validate(context.Background(), strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("unexpected error validating dump: %s", err)
+	}
+
+	expectedErrors := []Error{
+		{Line: 3, ElementID: 4, Message: "out-of-order reference: inV 3 refers to a vertex defined later on line 4"},
+		{Line: 4, ElementID: 3, Message: "range ends before it starts"},
+		{Line: 5, ElementID: 5, Message: "inV 4 refers to an edge"},
+	}
+	if diff := cmp.Diff(expectedErrors, errs); diff != "" {
+		t.Errorf("unexpected validation errors (-want +got):\n%s", diff)
+	}
+}
+
+func TestValidateInvalidOutV(t *testing.T) {
+	input := strings.Join([]string{
+		`{"id": 1, "type": "vertex", "label": "metaData", "version": "0.4.3", "projectRoot": "file:///test"}`,
+		`{"id": 2, "type": "vertex", "label": "range", "start": {"line": 1, "character": 2}, "end": {"line": 1, "character": 5}}`,
+		`{"id": 3, "type": "vertex", "label": "range", "start": {"line": 2, "character": 2}, "end": {"line": 2, "character": 5}}`,
+		`{"id": 4, "type": "edge", "label": "contains", "outV": 42, "inVs": [2, 3, 43]}`,
+		`{"id": 5, "type": "edge", "label": "contains", "outV": 7, "inVs": [44]}`,
+		`{"id": 6, "type": "vertex", "label": "range", "start": {"line": 3, "character": 2}, "end": {"line": 3, "character": 5}}`,
+		`{"id": 7, "type": "vertex", "label": "document", "uri": "file:///test/foo.go"}`,
+		`{"id": 8, "type": "edge", "label": "contains", "outV": 9, "inVs": [6]}`,
+		`{"id": 9, "type": "vertex", "label": "document", "uri": "file:///test/bar.go"}`,
+	}, "\n")
+
+	errs, err := Validate(context.Background(), strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("unexpected error validating dump: %s", err)
+	}
+
+	expectedErrors := []Error{
+		{Line: 4, ElementID: 4, Message: "dangling reference: outV 42 does not refer to any vertex"},
+		{Line: 4, ElementID: 4, Message: "dangling reference: inV 43 does not refer to any vertex"},
+		{Line: 5, ElementID: 5, Message: "out-of-order reference: outV 7 refers to a vertex defined later on line 7"},
+		{Line: 5, ElementID: 5, Message: "dangling reference: inV 44 does not refer to any vertex"},
+		{Line: 8, ElementID: 8, Message: "out-of-order reference: outV 9 refers to a vertex defined later on line 9"},
+	}
+	if diff := cmp.Diff(expectedErrors, errs); diff != "" {
+		t.Errorf("unexpected validation errors (-want +got):\n%s", diff)
+	}
+}
+
+func TestValidateSourceBounds(t *testing.T) {
+	input := strings.Join([]string{
+		`{"id": 1, "type": "vertex", "label": "metaData", "version": "0.4.3", "projectRoot": "file:///test"}`,
+		`{"id": 2, "type": "vertex", "label": "document", "uri": "file:///test/foo%20bar.go"}`,
+		`{"id": 3, "type": "vertex", "label": "range", "start": {"line": 0, "character": 5}, "end": {"line": 0, "character": 9}}`,
+		`{"id": 4, "type": "vertex", "label": "range", "start": {"line": 1, "character": 0}, "end": {"line": 1, "character": 12}}`,
+		`{"id": 5, "type": "vertex", "label": "range", "start": {"line": 3, "character": 0}, "end": {"line": 3, "character": 1}}`,
+		`{"id": 6, "type": "edge", "label": "contains", "outV": 2, "inVs": [3, 4, 5]}`,
+		`{"id": 7, "type": "vertex", "label": "document", "uri": "file:///test/missing.go"}`,
+		`{"id": 8, "type": "vertex", "label": "range", "start": {"line": 0, "character": 0}, "end": {"line": 0, "character": 1}}`,
+		`{"id": 9, "type": "edge", "label": "contains", "outV": 7, "inVs": [8]}`,
+	}, "\n")
+
+	readSource := func(path string) ([]byte, error) {
+		if path == "foo bar.go" {
+			return []byte("func \U0001F600()\r\nvar x = 1\n"), nil
+		}
+
+		return nil, os.ErrNotExist
+	}
+
+	errs, err := Validate(context.Background(), strings.NewReader(input), WithSourceReader(readSource))
+	if err != nil {
+		t.Fatalf("unexpected error validating dump: %s", err)
+	}
+
+	expectedErrors := []Error{
+		{Line: 4, ElementID: 4, Message: "range position 1:12 is outside of the document, whose line 1 has 9 characters"},
+		{Line: 5, ElementID: 5, Message: "range position 3:0 is outside of the document, which has 3 lines"},
+		{Line: 7, ElementID: 7, Message: "cannot read source of file:///test/missing.go: file does not exist"},
+	}
+	if diff := cmp.Diff(expectedErrors, errs); diff != "" {
+		t.Errorf("unexpected validation errors (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,234 @@
+// Package visualization renders an LSIF dump, or the neighborhood of a single vertex
+// within it, as a Graphviz DOT graph.
+package visualization
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/sourcegraph/lsif-protocol/reader"
+)
+
+// Option configures a visualization.
+type Option func(*options)
+
+type options struct {
+	root    int
+	maxHops int
+}
+
+// WithNeighborhood restricts the visualization to the vertices that are at most the given
+// number of edges away from the vertex with the given identifier (in either direction).
+func WithNeighborhood(root, maxHops int) Option {
+	return func(o *options) {
+		o.root = root
+		o.maxHops = maxHops
+	}
+}
+
+// maxLabelLength is the maximum number of characters of payload text included in a vertex label.
+const maxLabelLength = 40
+
+type vertex struct {
+	id      int
+	label   string
+	payload interface{}
+}
+
+type edge struct {
+	id    int
+	label string
+	outV  int
+	inVs  []int
+}
+
+// Visualize reads the given LSIF dump and writes a DOT representation of its graph to the
+// given writer.
+func Visualize(ctx context.Context, r io.Reader, w io.Writer, opts ...Option) error {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	vertices := map[int]vertex{}
+	var edges []edge
+
+	for pair := range reader.Read(ctx, r) {
+		if pair.Err != nil {
+			return pair.Err
+		}
+		element := pair.Element
+
+		switch element.Type {
+		case "vertex":
+			vertices[element.ID] = vertex{id: element.ID, label: element.Label, payload: element.Payload}
+
+		case "edge":
+			payload, ok := element.Payload.(reader.Edge)
+			if !ok {
+				continue
+			}
+
+			inVs := payload.InVs
+			if payload.InV != 0 {
+				inVs = append([]int{payload.InV}, inVs...)
+			}
+
+			edges = append(edges, edge{id: element.ID, label: element.Label, outV: payload.OutV, inVs: inVs})
+		}
+	}
+
+	included := func(id int) bool { return true }
+	if o.root != 0 {
+		if _, ok := vertices[o.root]; !ok {
+			return fmt.Errorf("no vertex with identifier %d", o.root)
+		}
+
+		neighborhood := neighborhood(edges, o.root, o.maxHops)
+		included = func(id int) bool {
+			_, ok := neighborhood[id]
+			return ok
+		}
+	}
+
+	return render(w, vertices, edges, included)
+}
+
+// neighborhood returns the set of vertices reachable from the given root by traversing at
+// most maxHops edges in either direction.
+func neighborhood(edges []edge, root, maxHops int) map[int]struct{} {
+	adjacent := map[int][]int{}
+	for _, e := range edges {
+		for _, inV := range e.inVs {
+			adjacent[e.outV] = append(adjacent[e.outV], inV)
+			adjacent[inV] = append(adjacent[inV], e.outV)
+		}
+	}
+
+	visited := map[int]struct{}{root: {}}
+	frontier := []int{root}
+
+	for hop := 0; hop < maxHops && len(frontier) > 0; hop++ {
+		var next []int
+		for _, id := range frontier {
+			for _, neighbor := range adjacent[id] {
+				if _, ok := visited[neighbor]; !ok {
+					visited[neighbor] = struct{}{}
+					next = append(next, neighbor)
+				}
+			}
+		}
+
+		frontier = next
+	}
+
+	return visited
+}
+
+func render(w io.Writer, vertices map[int]vertex, edges []edge, included func(id int) bool) error {
+	ids := make([]int, 0, len(vertices))
+	for id := range vertices {
+		if included(id) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+
+	var b strings.Builder
+	b.WriteString("digraph lsif {\n")
+	b.WriteString("\tnode [shape=box, fontname=\"monospace\"];\n")
+
+	for _, id := range ids {
+		fmt.Fprintf(&b, "\t%d [label=%s];\n", id, quote(vertexLabel(vertices[id])))
+	}
+
+	for _, e := range edges {
+		if !included(e.outV) {
+			continue
+		}
+
+		for _, inV := range e.inVs {
+			if included(inV) {
+				fmt.Fprintf(&b, "\t%d -> %d [label=%s];\n", e.outV, inV, quote(fmt.Sprintf("%d: %s", e.id, e.label)))
+			}
+		}
+	}
+
+	b.WriteString("}\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// vertexLabel describes a vertex by its identifier, label, and a summary of its payload.
+func vertexLabel(v vertex) string {
+	label := fmt.Sprintf("%d: %s", v.id, v.label)
+	if summary := summarize(v.payload); summary != "" {
+		label += "\n" + summary
+	}
+
+	return label
+}
+
+func summarize(payload interface{}) string {
+	switch p := payload.(type) {
+	case reader.MetaData:
+		return p.ProjectRoot
+	case reader.Project:
+		return p.Kind
+	case reader.Document:
+		return truncate(p.URI)
+	case string:
+		return truncate(p)
+	case reader.Range:
+		s := fmt.Sprintf("%d:%d-%d:%d", p.StartLine, p.StartCharacter, p.EndLine, p.EndCharacter)
+		if p.Tag != nil {
+			s += fmt.Sprintf("\n%s %s", p.Tag.Type, truncate(p.Tag.Text))
+		}
+		return s
+	case reader.Moniker:
+		return fmt.Sprintf("%s %s:%s", p.Kind, p.Scheme, truncate(p.Identifier))
+	case reader.PackageInformation:
+		return fmt.Sprintf("%s@%s", p.Name, p.Version)
+	case reader.Event:
+		return fmt.Sprintf("%s %s %d", p.Kind, p.Scope, p.Data)
+	case []reader.Diagnostic:
+		return fmt.Sprintf("%d diagnostics", len(p))
+	case []reader.FoldingRange:
+		return fmt.Sprintf("%d folding ranges", len(p))
+	case []reader.DocumentLink:
+		return fmt.Sprintf("%d document links", len(p))
+	case []reader.DocumentSymbol:
+		return fmt.Sprintf("%d document symbols", len(p))
+	case []reader.RangeBasedDocumentSymbol:
+		return fmt.Sprintf("%d document symbols", len(p))
+	}
+
+	return ""
+}
+
+// truncate returns the first line of the given text, limited to maxLabelLength characters.
+func truncate(text string) string {
+	if i := strings.IndexByte(text, '\n'); i >= 0 {
+		text = text[:i] + "..."
+	}
+
+	if utf8.RuneCountInString(text) > maxLabelLength {
+		text = string([]rune(text)[:maxLabelLength]) + "..."
+	}
+
+	return text
+}
+
+// quote returns the given text as a DOT string literal.
+func quote(text string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	return `"` + replacer.Replace(text) + `"`
+}
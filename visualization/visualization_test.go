@@ -0,0 +1,68 @@
+package visualization
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+var testDump = strings.Join([]string{
+	`{"id": 1, "type": "vertex", "label": "metaData", "version": "0.4.3", "projectRoot": "file:///test"}`,
+	`{"id": 2, "type": "vertex", "label": "document", "uri": "file:///test/foo.go"}`,
+	`{"id": 3, "type": "vertex", "label": "range", "start": {"line": 1, "character": 5}, "end": {"line": 1, "character": 8}}`,
+	`{"id": 4, "type": "edge", "label": "contains", "outV": 2, "inVs": [3]}`,
+	`{"id": 5, "type": "vertex", "label": "resultSet"}`,
+	`{"id": 6, "type": "edge", "label": "next", "outV": 3, "inV": 5}`,
+	`{"id": 7, "type": "vertex", "label": "moniker", "kind": "export", "scheme": "gomod", "identifier": "test:Foo"}`,
+	`{"id": 8, "type": "edge", "label": "moniker", "outV": 5, "inV": 7}`,
+	`{"id": 9, "type": "vertex", "label": "hoverResult", "result": {"contents": "say \"hi\""}}`,
+	`{"id": 10, "type": "edge", "label": "textDocument/hover", "outV": 5, "inV": 9}`,
+}, "\n")
+
+func TestVisualize(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Visualize(context.Background(), strings.NewReader(testDump), &buf); err != nil {
+		t.Fatalf("unexpected error visualizing dump: %s", err)
+	}
+
+	expected := `digraph lsif {
+	node [shape=box, fontname="monospace"];
+	1 [label="1: metaData\nfile:///test"];
+	2 [label="2: document\nfile:///test/foo.go"];
+	3 [label="3: range\n1:5-1:8"];
+	5 [label="5: resultSet"];
+	7 [label="7: moniker\nexport gomod:test:Foo"];
+	9 [label="9: hoverResult\nsay \"hi\""];
+	2 -> 3 [label="4: contains"];
+	3 -> 5 [label="6: next"];
+	5 -> 7 [label="8: moniker"];
+	5 -> 9 [label="10: textDocument/hover"];
+}
+`
+	if diff := cmp.Diff(expected, buf.String()); diff != "" {
+		t.Errorf("unexpected output (-want +got):\n%s", diff)
+	}
+}
+
+func TestVisualizeNeighborhood(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Visualize(context.Background(), strings.NewReader(testDump), &buf, WithNeighborhood(3, 1)); err != nil {
+		t.Fatalf("unexpected error visualizing dump: %s", err)
+	}
+
+	expected := `digraph lsif {
+	node [shape=box, fontname="monospace"];
+	2 [label="2: document\nfile:///test/foo.go"];
+	3 [label="3: range\n1:5-1:8"];
+	5 [label="5: resultSet"];
+	2 -> 3 [label="4: contains"];
+	3 -> 5 [label="6: next"];
+}
+`
+	if diff := cmp.Diff(expected, buf.String()); diff != "" {
+		t.Errorf("unexpected output (-want +got):\n%s", diff)
+	}
+}
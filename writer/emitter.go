@@ -10,156 +10,322 @@ import (
 // JSONWriter instance. Use of this struct guarantees that unique identifiers
 // are generated for each constructed element.
 type Emitter struct {
-	writer JSONWriter
-	id     uint64
+	writer      JSONWriter
+	ids         IDAllocator
+	stringIDs   *protocol.StringIDEncoder
+	uris        URIPolicy
+	numElements uint64
 }
 
-func NewEmitter(writer JSONWriter) *Emitter {
-	return &Emitter{
+// EmitterOption configures an Emitter.
+type EmitterOption func(e *Emitter)
+
+// WithIDAllocator sets the source of identifiers of the constructed elements. By
+// default, identifiers are allocated sequentially starting from 1.
+func WithIDAllocator(ids IDAllocator) EmitterOption {
+	return func(e *Emitter) {
+		e.ids = ids
+	}
+}
+
+// WithStringIDs causes the identifiers of the constructed elements, and the identifiers
+// they refer to, to be written as strings with the given prefix instead of as numbers.
+func WithStringIDs(prefix string) EmitterOption {
+	return func(e *Emitter) {
+		e.stringIDs = protocol.NewStringIDEncoder(prefix)
+	}
+}
+
+// WithURIPolicy sets the policy used to convert the paths given to EmitDocument into
+// document URIs. By default, URIs are constructed with FileURIs.
+func WithURIPolicy(policy URIPolicy) EmitterOption {
+	return func(e *Emitter) {
+		e.uris = policy
+	}
+}
+
+func NewEmitter(writer JSONWriter, options ...EmitterOption) *Emitter {
+	e := &Emitter{
 		writer: writer,
+		ids:    NewSequentialIDAllocator(0),
+		uris:   FileURIs(),
+	}
+
+	for _, option := range options {
+		option(e)
 	}
+
+	return e
 }
 
 func (e *Emitter) EmitMetaData(root string, info protocol.ToolInfo) uint64 {
 	id := e.nextID()
-	e.writer.Write(protocol.NewMetaData(id, root, info))
+	e.write(protocol.NewMetaData(id, root, info))
 	return id
 }
 
 func (e *Emitter) EmitProject(languageID string) uint64 {
 	id := e.nextID()
-	e.writer.Write(protocol.NewProject(id, languageID))
+	e.write(protocol.NewProject(id, languageID))
 	return id
 }
 
 func (e *Emitter) EmitDocument(languageID, path string) uint64 {
 	id := e.nextID()
-	e.writer.Write(protocol.NewDocument(id, languageID, "file://"+path))
+	e.write(protocol.NewDocument(id, languageID, e.uris(path)))
 	return id
 }
 
 func (e *Emitter) EmitRange(start, end protocol.Pos) uint64 {
 	id := e.nextID()
-	e.writer.Write(protocol.NewRange(id, start, end))
+	e.write(protocol.NewRange(id, start, end))
+	return id
+}
+
+func (e *Emitter) EmitRangeWithTag(start, end protocol.Pos, tag protocol.RangeTag) uint64 {
+	id := e.nextID()
+	e.write(protocol.NewRangeWithTag(id, start, end, tag))
 	return id
 }
 
 func (e *Emitter) EmitResultSet() uint64 {
 	id := e.nextID()
-	e.writer.Write(protocol.NewResultSet(id))
+	e.write(protocol.NewResultSet(id))
 	return id
 }
 
 func (e *Emitter) EmitHoverResult(contents []protocol.MarkedString) uint64 {
 	id := e.nextID()
-	e.writer.Write(protocol.NewHoverResult(id, contents))
+	e.write(protocol.NewHoverResult(id, contents))
 	return id
 }
 
 func (e *Emitter) EmitTextDocumentHover(outV, inV uint64) uint64 {
 	id := e.nextID()
-	e.writer.Write(protocol.NewTextDocumentHover(id, outV, inV))
+	e.write(protocol.NewTextDocumentHover(id, outV, inV))
 	return id
 }
 
 func (e *Emitter) EmitDefinitionResult() uint64 {
 	id := e.nextID()
-	e.writer.Write(protocol.NewDefinitionResult(id))
+	e.write(protocol.NewDefinitionResult(id))
 	return id
 }
 
 func (e *Emitter) EmitTypeDefinitionResult() uint64 {
 	id := e.nextID()
-	e.writer.Write(protocol.NewTypeDefinitionResult(id))
+	e.write(protocol.NewTypeDefinitionResult(id))
 	return id
 }
 
 func (e *Emitter) EmitTextDocumentDefinition(outV, inV uint64) uint64 {
 	id := e.nextID()
-	e.writer.Write(protocol.NewTextDocumentDefinition(id, outV, inV))
+	e.write(protocol.NewTextDocumentDefinition(id, outV, inV))
 	return id
 }
 
 func (e *Emitter) EmitTextDocumentTypeDefinition(outV, inV uint64) uint64 {
 	id := e.nextID()
-	e.writer.Write(protocol.NewTextDocumentTypeDefinition(id, outV, inV))
+	e.write(protocol.NewTextDocumentTypeDefinition(id, outV, inV))
 	return id
 }
 
 func (e *Emitter) EmitReferenceResult() uint64 {
 	id := e.nextID()
-	e.writer.Write(protocol.NewReferenceResult(id))
+	e.write(protocol.NewReferenceResult(id))
 	return id
 }
 
 func (e *Emitter) EmitTextDocumentReferences(outV, inV uint64) uint64 {
 	id := e.nextID()
-	e.writer.Write(protocol.NewTextDocumentReferences(id, outV, inV))
+	e.write(protocol.NewTextDocumentReferences(id, outV, inV))
+	return id
+}
+
+func (e *Emitter) EmitDeclarationResult() uint64 {
+	id := e.nextID()
+	e.write(protocol.NewDeclarationResult(id))
+	return id
+}
+
+func (e *Emitter) EmitTextDocumentDeclaration(outV, inV uint64) uint64 {
+	id := e.nextID()
+	e.write(protocol.NewTextDocumentDeclaration(id, outV, inV))
+	return id
+}
+
+func (e *Emitter) EmitImplementationResult() uint64 {
+	id := e.nextID()
+	e.write(protocol.NewImplementationResult(id))
+	return id
+}
+
+func (e *Emitter) EmitTextDocumentImplementation(outV, inV uint64) uint64 {
+	id := e.nextID()
+	e.write(protocol.NewTextDocumentImplementation(id, outV, inV))
+	return id
+}
+
+func (e *Emitter) EmitDocumentSymbolResult(result []protocol.DocumentSymbol) uint64 {
+	id := e.nextID()
+	e.write(protocol.NewDocumentSymbolResult(id, result))
+	return id
+}
+
+func (e *Emitter) EmitRangeBasedDocumentSymbolResult(result []protocol.RangeBasedDocumentSymbol) uint64 {
+	id := e.nextID()
+	e.write(protocol.NewRangeBasedDocumentSymbolResult(id, result))
+	return id
+}
+
+func (e *Emitter) EmitTextDocumentDocumentSymbol(outV, inV uint64) uint64 {
+	id := e.nextID()
+	e.write(protocol.NewTextDocumentDocumentSymbol(id, outV, inV))
+	return id
+}
+
+func (e *Emitter) EmitFoldingRangeResult(result []protocol.FoldingRange) uint64 {
+	id := e.nextID()
+	e.write(protocol.NewFoldingRangeResult(id, result))
+	return id
+}
+
+func (e *Emitter) EmitTextDocumentFoldingRange(outV, inV uint64) uint64 {
+	id := e.nextID()
+	e.write(protocol.NewTextDocumentFoldingRange(id, outV, inV))
+	return id
+}
+
+func (e *Emitter) EmitDocumentLinkResult(result []protocol.DocumentLink) uint64 {
+	id := e.nextID()
+	e.write(protocol.NewDocumentLinkResult(id, result))
+	return id
+}
+
+func (e *Emitter) EmitTextDocumentDocumentLink(outV, inV uint64) uint64 {
+	id := e.nextID()
+	e.write(protocol.NewTextDocumentDocumentLink(id, outV, inV))
+	return id
+}
+
+func (e *Emitter) EmitDiagnosticResult(result []protocol.Diagnostic) uint64 {
+	id := e.nextID()
+	e.write(protocol.NewDiagnosticResult(id, result))
+	return id
+}
+
+func (e *Emitter) EmitTextDocumentDiagnostic(outV, inV uint64) uint64 {
+	id := e.nextID()
+	e.write(protocol.NewTextDocumentDiagnostic(id, outV, inV))
 	return id
 }
 
 func (e *Emitter) EmitItem(outV uint64, inVs []uint64, docID uint64) uint64 {
 	id := e.nextID()
-	e.writer.Write(protocol.NewItem(id, outV, inVs, docID))
+	e.write(protocol.NewItem(id, outV, inVs, docID))
 	return id
 }
 
 func (e *Emitter) EmitItemOfDefinitions(outV uint64, inVs []uint64, docID uint64) uint64 {
 	id := e.nextID()
-	e.writer.Write(protocol.NewItemOfDefinitions(id, outV, inVs, docID))
+	e.write(protocol.NewItemOfDefinitions(id, outV, inVs, docID))
 	return id
 }
 
 func (e *Emitter) EmitItemOfReferences(outV uint64, inVs []uint64, docID uint64) uint64 {
 	id := e.nextID()
-	e.writer.Write(protocol.NewItemOfReferences(id, outV, inVs, docID))
+	e.write(protocol.NewItemOfReferences(id, outV, inVs, docID))
 	return id
 }
 
 func (e *Emitter) EmitMoniker(kind, scheme, identifier string) uint64 {
 	id := e.nextID()
-	e.writer.Write(protocol.NewMoniker(id, kind, scheme, identifier))
+	e.write(protocol.NewMoniker(id, kind, scheme, identifier))
 	return id
 }
 
 func (e *Emitter) EmitMonikerEdge(outV, inV uint64) uint64 {
 	id := e.nextID()
-	e.writer.Write(protocol.NewMonikerEdge(id, outV, inV))
+	e.write(protocol.NewMonikerEdge(id, outV, inV))
 	return id
 }
 
 func (e *Emitter) EmitPackageInformation(packageName, scheme, version string) uint64 {
 	id := e.nextID()
-	e.writer.Write(protocol.NewPackageInformation(id, packageName, scheme, version))
+	e.write(protocol.NewPackageInformation(id, packageName, scheme, version))
 	return id
 }
 
 func (e *Emitter) EmitPackageInformationEdge(outV, inV uint64) uint64 {
 	id := e.nextID()
-	e.writer.Write(protocol.NewPackageInformationEdge(id, outV, inV))
+	e.write(protocol.NewPackageInformationEdge(id, outV, inV))
 	return id
 }
 
 func (e *Emitter) EmitContains(outV uint64, inVs []uint64) uint64 {
 	id := e.nextID()
-	e.writer.Write(protocol.NewContains(id, outV, inVs))
+	e.write(protocol.NewContains(id, outV, inVs))
 	return id
 }
 
 func (e *Emitter) EmitNext(outV, inV uint64) uint64 {
 	id := e.nextID()
-	e.writer.Write(protocol.NewNext(id, outV, inV))
+	e.write(protocol.NewNext(id, outV, inV))
 	return id
 }
 
+func (e *Emitter) EmitEvent(kind protocol.EventKind, scope protocol.EventScope, data uint64) uint64 {
+	id := e.nextID()
+	e.write(protocol.NewEvent(id, kind, scope, data))
+	return id
+}
+
+// WithProjectEvents emits a begin event for the given project, invokes f, and
+// then emits the matching end event. The end event is emitted even if f returns
+// an error or panics so that events in the output are always balanced.
+func (e *Emitter) WithProjectEvents(projectID uint64, f func() error) error {
+	return e.withEvents(protocol.EventScopeProject, projectID, f)
+}
+
+// WithDocumentEvents emits a begin event for the given document, invokes f, and
+// then emits the matching end event. The end event is emitted even if f returns
+// an error or panics so that events in the output are always balanced.
+func (e *Emitter) WithDocumentEvents(documentID uint64, f func() error) error {
+	return e.withEvents(protocol.EventScopeDocument, documentID, f)
+}
+
+func (e *Emitter) withEvents(scope protocol.EventScope, data uint64, f func() error) error {
+	e.EmitEvent(protocol.EventKindBegin, scope, data)
+	defer e.EmitEvent(protocol.EventKindEnd, scope, data)
+	return f()
+}
+
+// NumElements returns the number of elements constructed by this emitter.
 func (e *Emitter) NumElements() uint64 {
-	return atomic.LoadUint64(&e.id)
+	return atomic.LoadUint64(&e.numElements)
+}
+
+// Err returns the first error encountered by the underlying JSONWriter, if any.
+// Once this value is non-nil, emitted elements are discarded and the caller
+// should abort. This method always returns nil if the JSONWriter does not
+// implement ErrorReporter.
+func (e *Emitter) Err() error {
+	return Err(e.writer)
 }
 
 func (e *Emitter) Flush() error {
 	return e.writer.Flush()
 }
 
+func (e *Emitter) write(element interface{}) {
+	if e.stringIDs != nil {
+		element = e.stringIDs.Wrap(element)
+	}
+
+	e.writer.Write(element)
+}
+
 func (e *Emitter) nextID() uint64 {
-	return atomic.AddUint64(&e.id, 1)
+	atomic.AddUint64(&e.numElements, 1)
+	return e.ids.NextID()
 }
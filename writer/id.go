@@ -0,0 +1,48 @@
+package writer
+
+import "sync/atomic"
+
+// IDAllocator generates the identifiers of the elements constructed by an Emitter.
+// Implementations must be safe for concurrent use and must never return the same
+// identifier twice.
+type IDAllocator interface {
+	NextID() uint64
+}
+
+type sequentialIDAllocator struct {
+	id uint64
+}
+
+// NewSequentialIDAllocator creates an IDAllocator that returns the identifiers
+// offset+1, offset+2, and so on. Emitters writing to the same dump can be given
+// disjoint ranges of identifiers by using offsets that are further apart than the
+// number of elements each emitter constructs.
+func NewSequentialIDAllocator(offset uint64) IDAllocator {
+	return &sequentialIDAllocator{id: offset}
+}
+
+func (a *sequentialIDAllocator) NextID() uint64 {
+	return atomic.AddUint64(&a.id, 1)
+}
+
+type partitionedIDAllocator struct {
+	n      uint64
+	first  uint64
+	stride uint64
+}
+
+// NewPartitionedIDAllocator creates an IDAllocator that returns only the identifiers
+// belonging to the given partition (in the range [0, partitions)). Each identifier is
+// congruent to partition+1 modulo partitions, so emitters given distinct partitions of
+// the same count never produce colliding identifiers.
+func NewPartitionedIDAllocator(partition, partitions uint64) IDAllocator {
+	if partitions == 0 || partition >= partitions {
+		panic("writer: partition out of range")
+	}
+
+	return &partitionedIDAllocator{first: partition + 1, stride: partitions}
+}
+
+func (a *partitionedIDAllocator) NextID() uint64 {
+	return a.first + (atomic.AddUint64(&a.n, 1)-1)*a.stride
+}
@@ -0,0 +1,92 @@
+package writer
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	protocol "github.com/sourcegraph/lsif-protocol"
+)
+
+func TestSequentialIDAllocator(t *testing.T) {
+	a := NewSequentialIDAllocator(1000)
+
+	var ids []uint64
+	for i := 0; i < 3; i++ {
+		ids = append(ids, a.NextID())
+	}
+
+	if diff := cmp.Diff([]uint64{1001, 1002, 1003}, ids); diff != "" {
+		t.Errorf("unexpected ids (-want +got):\n%s", diff)
+	}
+}
+
+func TestPartitionedIDAllocator(t *testing.T) {
+	var ids [][]uint64
+	for partition := uint64(0); partition < 3; partition++ {
+		a := NewPartitionedIDAllocator(partition, 3)
+
+		var partitionIDs []uint64
+		for i := 0; i < 3; i++ {
+			partitionIDs = append(partitionIDs, a.NextID())
+		}
+		ids = append(ids, partitionIDs)
+	}
+
+	expected := [][]uint64{
+		{1, 4, 7},
+		{2, 5, 8},
+		{3, 6, 9},
+	}
+	if diff := cmp.Diff(expected, ids); diff != "" {
+		t.Errorf("unexpected ids (-want +got):\n%s", diff)
+	}
+}
+
+func TestEmitterWithIDAllocator(t *testing.T) {
+	var buf bytes.Buffer
+	e := NewEmitter(NewJSONWriter(&buf), WithIDAllocator(NewSequentialIDAllocator(100)))
+	resultSetID := e.EmitResultSet()
+	e.EmitTextDocumentHover(resultSetID, e.EmitHoverResult(nil))
+
+	if err := e.Flush(); err != nil {
+		t.Fatalf("unexpected error flushing emitter: %s", err)
+	}
+
+	expected := "" +
+		`{"id":101,"type":"vertex","label":"resultSet"}` + "\n" +
+		`{"id":102,"type":"vertex","label":"hoverResult","result":{"contents":null}}` + "\n" +
+		`{"id":103,"type":"edge","label":"textDocument/hover","outV":101,"inV":102}` + "\n"
+	if diff := cmp.Diff(expected, buf.String()); diff != "" {
+		t.Errorf("unexpected output (-want +got):\n%s", diff)
+	}
+	if n := e.NumElements(); n != 3 {
+		t.Errorf("unexpected number of elements. want=%d have=%d", 3, n)
+	}
+}
+
+func TestEmitterWithStringIDs(t *testing.T) {
+	var buf bytes.Buffer
+	e := NewEmitter(NewJSONWriter(&buf), WithStringIDs("shard1-"))
+	documentID := e.EmitDocument("go", "/foo.go")
+	rangeID := e.EmitRange(protocol.Pos{Line: 1, Character: 2}, protocol.Pos{Line: 1, Character: 5})
+	e.EmitContains(documentID, []uint64{rangeID})
+	definitionResultID := e.EmitDefinitionResult()
+	e.EmitTextDocumentDefinition(rangeID, definitionResultID)
+	e.EmitItem(definitionResultID, []uint64{rangeID}, documentID)
+
+	if err := e.Flush(); err != nil {
+		t.Fatalf("unexpected error flushing emitter: %s", err)
+	}
+
+	expected := "" +
+		`{"id":"shard1-1","type":"vertex","label":"document","uri":"file:///foo.go","languageId":"go"}` + "\n" +
+		`{"id":"shard1-2","type":"vertex","label":"range","start":{"line":1,"character":2},"end":{"line":1,"character":5}}` + "\n" +
+		`{"id":"shard1-3","type":"edge","label":"contains","outV":"shard1-1","inVs":["shard1-2"]}` + "\n" +
+		`{"id":"shard1-4","type":"vertex","label":"definitionResult"}` + "\n" +
+		`{"id":"shard1-5","type":"edge","label":"textDocument/definition","outV":"shard1-2","inV":"shard1-4"}` + "\n" +
+		`{"id":"shard1-6","type":"edge","label":"item","outV":"shard1-4","inVs":["shard1-2"],"document":"shard1-1"}` + "\n"
+	if diff := cmp.Diff(expected, buf.String()); diff != "" {
+		t.Errorf("unexpected output (-want +got):\n%s", diff)
+	}
+}
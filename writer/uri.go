@@ -0,0 +1,132 @@
+package writer
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// URIPolicy converts the path of a document into the URI written to its document vertex.
+type URIPolicy func(path string) string
+
+// FileURIs creates a URIPolicy that converts each absolute path into a file URI with
+// FileURI. This is the default policy of an Emitter.
+func FileURIs() URIPolicy {
+	return FileURI
+}
+
+// FileURI returns the file URI of the given absolute path (RFC 8089). Each path segment is
+// percent-encoded as described in RFC 3986. Windows paths are accepted regardless of the
+// current platform: a path with a drive letter such as C:\src\foo.go becomes
+// file:///C:/src/foo.go and a UNC path such as \\server\share\foo.go becomes
+// file://server/share/foo.go.
+func FileURI(path string) string {
+	windows := isWindowsPath(path)
+	if windows {
+		path = strings.Replace(path, `\`, "/", -1)
+	} else {
+		path = filepath.ToSlash(path)
+	}
+
+	host := ""
+	if windows && strings.HasPrefix(path, "//") {
+		// UNC paths name the host as the first segment
+		host, path = path[2:], "/"
+		if i := strings.IndexByte(host, '/'); i >= 0 {
+			host, path = host[:i], host[i:]
+		}
+	} else if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+
+	if hasDriveLetter(path[1:]) {
+		// Retain the colon of the drive letter, which would otherwise be encoded
+		return "file://" + host + path[:3] + escapePath(path[3:])
+	}
+
+	return "file://" + host + escapePath(path)
+}
+
+// isWindowsPath returns true if the given path begins with a drive letter or is a UNC path.
+func isWindowsPath(path string) bool {
+	return hasDriveLetter(path) || strings.HasPrefix(path, `\\`)
+}
+
+// hasDriveLetter returns true if the given path begins with a Windows drive letter and colon.
+func hasDriveLetter(path string) bool {
+	if len(path) < 2 || path[1] != ':' {
+		return false
+	}
+
+	c := path[0]
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
+
+// RelativeURIs creates a URIPolicy that produces relative URI references, resolved against
+// the project root, for paths relative to the given root directory. Each path segment is
+// percent-encoded as described in RFC 3986.
+func RelativeURIs(root string) URIPolicy {
+	return BaseURIs("", root)
+}
+
+// BaseURIs creates a URIPolicy that appends the path relative to the given root directory
+// to the given base URI (e.g., "https://example.com/repo/"). This allows documents to be
+// identified by a scheme other than file. Each path segment is percent-encoded as described
+// in RFC 3986.
+func BaseURIs(base, root string) URIPolicy {
+	return func(path string) string {
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			// The path cannot be made relative (e.g., it is on another volume)
+			rel = path
+		}
+
+		return base + escapePath(filepath.ToSlash(rel))
+	}
+}
+
+// escapePath percent-encodes each segment of the given slash-separated path.
+func escapePath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		segments[i] = escapePathSegment(segment)
+	}
+
+	return strings.Join(segments, "/")
+}
+
+const upperhex = "0123456789ABCDEF"
+
+// escapePathSegment percent-encodes the bytes of the given path segment that are not
+// unreserved characters or sub-delimiters (RFC 3986, section 3.3). Colons are encoded as
+// well so that a relative reference is never mistaken for a URI with a scheme.
+func escapePathSegment(segment string) string {
+	var b strings.Builder
+	for i := 0; i < len(segment); i++ {
+		if c := segment[i]; shouldEscape(c) {
+			b.WriteByte('%')
+			b.WriteByte(upperhex[c>>4])
+			b.WriteByte(upperhex[c&15])
+		} else {
+			b.WriteByte(c)
+		}
+	}
+
+	return b.String()
+}
+
+func shouldEscape(c byte) bool {
+	if 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' {
+		return false
+	}
+
+	switch c {
+	case '-', '.', '_', '~': // unreserved
+		return false
+	case '!', '$', '&', '\'', '(', ')', '*', '+', ',', ';', '=': // sub-delims
+		return false
+	case '@':
+		return false
+	}
+
+	return true
+}
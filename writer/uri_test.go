@@ -0,0 +1,49 @@
+package writer
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestURIPolicies(t *testing.T) {
+	testCases := []struct {
+		name     string
+		policy   URIPolicy
+		path     string
+		expected string
+	}{
+		{name: "file", policy: FileURIs(), path: "/tmp/build/foo.go", expected: "file:///tmp/build/foo.go"},
+		{name: "file escaped", policy: FileURIs(), path: "/tmp/my build/#1/100%/héllo.go", expected: "file:///tmp/my%20build/%231/100%25/h%C3%A9llo.go"},
+		{name: "file drive letter", policy: FileURIs(), path: `C:\Users\me\src\foo bar.go`, expected: "file:///C:/Users/me/src/foo%20bar.go"},
+		{name: "file unc", policy: FileURIs(), path: `\\server\share\foo.go`, expected: "file://server/share/foo.go"},
+		{name: "relative", policy: RelativeURIs("/tmp/build"), path: "/tmp/build/pkg/foo.go", expected: "pkg/foo.go"},
+		{name: "relative escaped", policy: RelativeURIs("/tmp/build"), path: "/tmp/build/a b/c#d?e%f:g.go", expected: "a%20b/c%23d%3Fe%25f%3Ag.go"},
+		{name: "relative sub-delims", policy: RelativeURIs("/tmp/build"), path: "/tmp/build/a+b,c@d.go", expected: "a+b,c@d.go"},
+		{name: "relative unicode", policy: RelativeURIs("/tmp/build"), path: "/tmp/build/héllo.go", expected: "h%C3%A9llo.go"},
+		{name: "relative outside root", policy: RelativeURIs("/tmp/build"), path: "/tmp/other/foo.go", expected: "../other/foo.go"},
+		{name: "base", policy: BaseURIs("https://example.com/repo/", "/tmp/build"), path: "/tmp/build/pkg/foo bar.go", expected: "https://example.com/repo/pkg/foo%20bar.go"},
+	}
+
+	for _, testCase := range testCases {
+		if uri := testCase.policy(testCase.path); uri != testCase.expected {
+			t.Errorf("unexpected uri for %s. want=%q have=%q", testCase.name, testCase.expected, uri)
+		}
+	}
+}
+
+func TestEmitterWithURIPolicy(t *testing.T) {
+	var buf bytes.Buffer
+	e := NewEmitter(NewJSONWriter(&buf), WithURIPolicy(RelativeURIs("/tmp/build")))
+	e.EmitDocument("go", "/tmp/build/foo bar.go")
+
+	if err := e.Flush(); err != nil {
+		t.Fatalf("unexpected error flushing emitter: %s", err)
+	}
+
+	expected := `{"id":1,"type":"vertex","label":"document","uri":"foo%20bar.go","languageId":"go"}` + "\n"
+	if diff := cmp.Diff(expected, buf.String()); diff != "" {
+		t.Errorf("unexpected output (-want +got):\n%s", diff)
+	}
+}
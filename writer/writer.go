@@ -2,6 +2,8 @@ package writer
 
 import (
 	"bufio"
+	"compress/gzip"
+	"context"
 	"io"
 	"sync"
 
@@ -13,21 +15,45 @@ var marshaller = jsoniter.ConfigFastest
 // JSONWriter serializes vertexes and edges into JSON and writes them to an
 // underlying writer as newline-delimited JSON.
 type JSONWriter interface {
-	// Write emits a single vertex or edge value.
+	// Write emits a single vertex or edge value. Once writing to the underlying
+	// writer has failed, values passed to Write are discarded.
 	Write(v interface{})
 
 	// Flush ensures that all elements have been written to the underlying writer.
 	Flush() error
 }
 
+// ErrorReporter is an optional interface implemented by JSONWriters that can report a
+// failure of the underlying writer before Flush is called. The JSONWriter returned by
+// NewJSONWriter implements this interface.
+type ErrorReporter interface {
+	// Err returns the first error encountered while writing to the underlying
+	// writer, if any. Callers emitting a large number of elements should check
+	// this value periodically so they can abort as soon as the sink fails.
+	Err() error
+}
+
+// Err returns the first error encountered by the given writer if it implements
+// ErrorReporter, and nil otherwise.
+func Err(w JSONWriter) error {
+	if reporter, ok := w.(ErrorReporter); ok {
+		return reporter.Err()
+	}
+
+	return nil
+}
+
 type jsonWriter struct {
-	wg             sync.WaitGroup
 	ch             chan (interface{})
+	done           chan struct{}
 	bufferedWriter *bufio.Writer
+	closer         io.Closer
+	m              sync.RWMutex
 	err            error
 }
 
 var _ JSONWriter = &jsonWriter{}
+var _ ErrorReporter = &jsonWriter{}
 
 // channelBufferSize is the number of elements that can be queued to be written.
 const channelBufferSize = 512
@@ -35,48 +61,127 @@ const channelBufferSize = 512
 // writerBufferSize is the size of the buffered writer wrapping output to the target file.
 const writerBufferSize = 4096
 
+// WriterOption configures a JSONWriter.
+type WriterOption func(*writerOptions)
+
+type writerOptions struct {
+	ctx  context.Context
+	gzip bool
+}
+
+// WithContext ties the lifetime of a JSONWriter to the given context. Once the
+// context is canceled, the encoder goroutine stops, pending and future writes are
+// discarded, and Err and Flush report the context's error.
+func WithContext(ctx context.Context) WriterOption {
+	return func(o *writerOptions) {
+		o.ctx = ctx
+	}
+}
+
+// WithGzip compresses the output of a JSONWriter with gzip. The gzip stream is
+// terminated when the writer is flushed.
+func WithGzip() WriterOption {
+	return func(o *writerOptions) {
+		o.gzip = true
+	}
+}
+
 // NewJSONWriter creates a new JSONWriter wrapping the given writer.
-func NewJSONWriter(w io.Writer) JSONWriter {
+func NewJSONWriter(w io.Writer, options ...WriterOption) JSONWriter {
+	opts := writerOptions{ctx: context.Background()}
+	for _, option := range options {
+		option(&opts)
+	}
+
+	var closer io.Closer
+	if opts.gzip {
+		gzipWriter := gzip.NewWriter(w)
+		w, closer = gzipWriter, gzipWriter
+	}
+
 	ch := make(chan interface{}, channelBufferSize)
+	done := make(chan struct{})
 	bufferedWriter := bufio.NewWriterSize(w, writerBufferSize)
-	jw := &jsonWriter{ch: ch, bufferedWriter: bufferedWriter}
+	jw := &jsonWriter{ch: ch, done: done, bufferedWriter: bufferedWriter, closer: closer}
 	encoder := marshaller.NewEncoder(bufferedWriter)
 
-	jw.wg.Add(1)
 	go func() {
-		defer jw.wg.Done()
-
-		for v := range ch {
-			if err := encoder.Encode(v); err != nil {
-				jw.err = err
-				break
+		defer close(done)
+
+		for {
+			select {
+			case v, ok := <-ch:
+				if !ok {
+					return
+				}
+
+				// Do not encode further values if the context was canceled while
+				// there was still work in the channel.
+				if err := opts.ctx.Err(); err != nil {
+					jw.setErr(err)
+					return
+				}
+
+				if err := encoder.Encode(v); err != nil {
+					jw.setErr(err)
+					return
+				}
+
+			case <-opts.ctx.Done():
+				jw.setErr(opts.ctx.Err())
+				return
 			}
 		}
-
-		for range ch {
-		}
 	}()
 
 	return jw
 }
 
-// Write emits a single vertex or edge value.
+// Write emits a single vertex or edge value. Once writing to the underlying
+// writer has failed, values passed to Write are discarded.
 func (jw *jsonWriter) Write(v interface{}) {
-	jw.ch <- v
+	select {
+	case jw.ch <- v:
+	case <-jw.done:
+	}
+}
+
+// Err returns the first error encountered while writing to the underlying writer, if any.
+func (jw *jsonWriter) Err() error {
+	jw.m.RLock()
+	defer jw.m.RUnlock()
+	return jw.err
 }
 
 // Flush ensures that all elements have been written to the underlying writer.
 func (jw *jsonWriter) Flush() error {
 	close(jw.ch)
-	jw.wg.Wait()
+	<-jw.done
 
-	if jw.err != nil {
-		return jw.err
+	if err := jw.Err(); err != nil {
+		return err
 	}
 
 	if err := jw.bufferedWriter.Flush(); err != nil {
+		jw.setErr(err)
 		return err
 	}
 
+	if jw.closer != nil {
+		if err := jw.closer.Close(); err != nil {
+			jw.setErr(err)
+			return err
+		}
+	}
+
 	return nil
 }
+
+func (jw *jsonWriter) setErr(err error) {
+	jw.m.Lock()
+	defer jw.m.Unlock()
+
+	if jw.err == nil {
+		jw.err = err
+	}
+}
@@ -0,0 +1,145 @@
+package writer
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	protocol "github.com/sourcegraph/lsif-protocol"
+)
+
+func TestJSONWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewJSONWriter(&buf)
+	w.Write(protocol.NewResultSet(1))
+	w.Write(protocol.NewNext(2, 3, 1))
+
+	if err := w.Flush(); err != nil {
+		t.Fatalf("unexpected error flushing writer: %s", err)
+	}
+
+	expected := "" +
+		`{"id":1,"type":"vertex","label":"resultSet"}` + "\n" +
+		`{"id":2,"type":"edge","label":"next","outV":3,"inV":1}` + "\n"
+	if diff := cmp.Diff(expected, buf.String()); diff != "" {
+		t.Errorf("unexpected output (-want +got):\n%s", diff)
+	}
+}
+
+func TestJSONWriterGzip(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewJSONWriter(&buf, WithGzip())
+	w.Write(protocol.NewResultSet(1))
+	w.Write(protocol.NewNext(2, 3, 1))
+
+	if err := w.Flush(); err != nil {
+		t.Fatalf("unexpected error flushing writer: %s", err)
+	}
+
+	r, err := gzip.NewReader(&buf)
+	if err != nil {
+		t.Fatalf("unexpected error opening gzip reader: %s", err)
+	}
+	contents, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatalf("unexpected error reading gzip content: %s", err)
+	}
+
+	expected := "" +
+		`{"id":1,"type":"vertex","label":"resultSet"}` + "\n" +
+		`{"id":2,"type":"edge","label":"next","outV":3,"inV":1}` + "\n"
+	if diff := cmp.Diff(expected, string(contents)); diff != "" {
+		t.Errorf("unexpected output (-want +got):\n%s", diff)
+	}
+}
+
+var errWriteFailed = errors.New("disk full")
+
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errWriteFailed
+}
+
+func TestJSONWriterError(t *testing.T) {
+	w := NewJSONWriter(failingWriter{})
+
+	// Write many more values than can be buffered by the channel. If values
+	// are not discarded after the first failure this will block forever.
+	for i := 0; i < channelBufferSize*100; i++ {
+		w.Write(protocol.NewResultSet(uint64(i)))
+	}
+
+	if err := Err(w); err != errWriteFailed {
+		t.Errorf("unexpected error. want=%q have=%v", errWriteFailed, err)
+	}
+	if err := w.Flush(); err != errWriteFailed {
+		t.Errorf("unexpected error flushing writer. want=%q have=%v", errWriteFailed, err)
+	}
+}
+
+// recordingWriter is a JSONWriter that does not implement ErrorReporter.
+type recordingWriter struct {
+	values []interface{}
+}
+
+func (w *recordingWriter) Write(v interface{}) { w.values = append(w.values, v) }
+func (w *recordingWriter) Flush() error        { return nil }
+
+func TestEmitterWithoutErrorReporter(t *testing.T) {
+	w := &recordingWriter{}
+	e := NewEmitter(w)
+	e.EmitResultSet()
+
+	if err := e.Err(); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+	if len(w.values) != 1 {
+		t.Errorf("unexpected number of values. want=%d have=%d", 1, len(w.values))
+	}
+}
+
+func TestEmitterErrorFailsFast(t *testing.T) {
+	e := NewEmitter(NewJSONWriter(failingWriter{}))
+
+	deadline := time.Now().Add(time.Second * 10)
+	for e.Err() == nil {
+		if time.Now().After(deadline) {
+			t.Fatalf("emitter did not report error")
+		}
+
+		e.EmitResultSet()
+	}
+
+	if err := e.Err(); err != errWriteFailed {
+		t.Errorf("unexpected error. want=%q have=%v", errWriteFailed, err)
+	}
+	if err := e.Flush(); err != errWriteFailed {
+		t.Errorf("unexpected error flushing emitter. want=%q have=%v", errWriteFailed, err)
+	}
+}
+
+func TestJSONWriterContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	w := NewJSONWriter(&bytes.Buffer{}, WithContext(ctx))
+	w.Write(protocol.NewResultSet(1))
+	cancel()
+
+	// Write many more values than can be buffered by the channel. If the encoder
+	// goroutine does not stop on cancellation this will block forever.
+	for i := 0; i < channelBufferSize*100; i++ {
+		w.Write(protocol.NewResultSet(uint64(i)))
+	}
+
+	if err := w.Flush(); err != context.Canceled {
+		t.Errorf("unexpected error flushing writer. want=%q have=%v", context.Canceled, err)
+	}
+	if err := Err(w); err != context.Canceled {
+		t.Errorf("unexpected error. want=%q have=%v", context.Canceled, err)
+	}
+}